@@ -0,0 +1,263 @@
+package go_elasticsearch
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// AggregationTable is a flat view of (possibly nested) aggregation results.
+// Every leaf bucket becomes one row; bucket keys, doc counts and metric
+// values become columns named after the aggregations they come from.
+type AggregationTable struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// FlattenAggregations walks the aggregations of the search result and
+// returns them as a table. If docCount is true, a "<name>.doc_count" column
+// is added next to the key column of every bucket aggregation.
+func (this *SearchResult) FlattenAggregations(docCount bool) (*AggregationTable, error) {
+	if this.Aggregations == nil {
+		return &AggregationTable{}, nil
+	}
+	return FlattenAggregations(*this.Aggregations, docCount)
+}
+
+// FlattenAggregations flattens the raw "aggregations" section of a search
+// response, e.g. terms → date_histogram → sum, into a table.
+//
+// Single-value metrics produce a "<name>" column, multi-value metrics (stats,
+// percentiles, ...) produce one "<name>.<value>" column per value. Bucket
+// aggregations produce a "<name>" column holding the bucket key
+// (key_as_string if present), composite keys one "<name>.<source>" column
+// per source.
+//
+// A bucket without sub-buckets, e.g. a terms bucket whose date_histogram is
+// empty, still produces a row, with empty columns for its children. Sibling
+// bucket aggregations are not combined: each produces its own rows, in
+// which the columns of the other siblings are empty.
+func FlattenAggregations(data json.RawMessage, docCount bool) (*AggregationTable, error) {
+	aggs := make(map[string]interface{})
+	if len(data) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&aggs); err != nil {
+			return nil, err
+		}
+	}
+	flattener := &aggregationFlattener{
+		docCount: docCount,
+		index:    make(map[string]int),
+	}
+	flattener.walk(aggs, make(map[string]interface{}))
+	return flattener.table(), nil
+}
+
+// WriteCSV writes the table, including a header line, as CSV.
+func (this *AggregationTable) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(this.Columns); err != nil {
+		return err
+	}
+	record := make([]string, len(this.Columns))
+	for _, row := range this.Rows {
+		for i, value := range row {
+			switch v := value.(type) {
+			case nil:
+				record[i] = ""
+			case string:
+				record[i] = v
+			default:
+				record[i] = fmt.Sprint(v)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+type aggregationFlattener struct {
+	docCount bool
+	columns  []string
+	index    map[string]int
+	rows     []map[string]interface{}
+}
+
+// walk adds the metrics found in aggs to row and descends into every bucket
+// aggregation. A row is emitted when no bucket aggregation is left, or when
+// the bucket aggregations have no buckets.
+func (this *aggregationFlattener) walk(aggs map[string]interface{}, row map[string]interface{}) {
+	row = copyRow(row)
+	names := make([]string, 0, len(aggs))
+	for name, value := range aggs {
+		// "key" holds the bucket key of composite buckets, not an aggregation
+		if _, ok := value.(map[string]interface{}); ok && name != "key" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var (
+		buckets = make([]string, 0)
+		singles = make([]string, 0)
+	)
+	for _, name := range names {
+		agg := aggs[name].(map[string]interface{})
+		if _, ok := agg["buckets"]; ok {
+			buckets = append(buckets, name)
+		} else if _, ok := agg["doc_count"]; ok {
+			singles = append(singles, name)
+		} else {
+			this.metric(row, name, agg)
+		}
+	}
+
+	if len(buckets) == 0 && len(singles) == 0 {
+		this.rows = append(this.rows, row)
+		return
+	}
+	emitted := len(this.rows)
+	for _, name := range singles {
+		bucket := aggs[name].(map[string]interface{})
+		child := copyRow(row)
+		if this.docCount {
+			this.set(child, name+".doc_count", bucket["doc_count"])
+		}
+		this.walk(bucket, child)
+	}
+	for _, name := range buckets {
+		switch t := aggs[name].(map[string]interface{})["buckets"].(type) {
+		case []interface{}:
+			for _, item := range t {
+				if bucket, ok := item.(map[string]interface{}); ok {
+					this.bucket(row, name, bucketKey(bucket), bucket)
+				}
+			}
+		case map[string]interface{}:
+			keys := make([]string, 0, len(t))
+			for key := range t {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				if bucket, ok := t[key].(map[string]interface{}); ok {
+					this.bucket(row, name, key, bucket)
+				}
+			}
+		}
+	}
+	if len(this.rows) == emitted {
+		// all bucket aggregations are empty, keep the row without children
+		this.rows = append(this.rows, row)
+	}
+}
+
+func (this *aggregationFlattener) bucket(row map[string]interface{}, name string, key interface{}, bucket map[string]interface{}) {
+	child := copyRow(row)
+	if composite, ok := key.(map[string]interface{}); ok {
+		sources := make([]string, 0, len(composite))
+		for source := range composite {
+			sources = append(sources, source)
+		}
+		sort.Strings(sources)
+		for _, source := range sources {
+			this.set(child, name+"."+source, composite[source])
+		}
+	} else {
+		this.set(child, name, key)
+	}
+	if this.docCount {
+		this.set(child, name+".doc_count", bucket["doc_count"])
+	}
+	this.walk(bucket, child)
+}
+
+func (this *aggregationFlattener) metric(row map[string]interface{}, name string, agg map[string]interface{}) {
+	if value, ok := agg["value"]; ok {
+		this.set(row, name, value)
+		return
+	}
+	if values, ok := agg["values"]; ok {
+		switch t := values.(type) {
+		case map[string]interface{}:
+			keys := make([]string, 0, len(t))
+			for key := range t {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				this.set(row, name+"."+key, t[key])
+			}
+		case []interface{}:
+			for _, item := range t {
+				if v, ok := item.(map[string]interface{}); ok {
+					this.set(row, fmt.Sprintf("%s.%v", name, v["key"]), v["value"])
+				}
+			}
+		}
+		return
+	}
+	keys := make([]string, 0, len(agg))
+	for key, value := range agg {
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+		default:
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		this.set(row, name+"."+key, agg[key])
+	}
+}
+
+func (this *aggregationFlattener) set(row map[string]interface{}, column string, value interface{}) {
+	if _, ok := this.index[column]; !ok {
+		this.index[column] = len(this.columns)
+		this.columns = append(this.columns, column)
+	}
+	row[column] = value
+}
+
+func (this *aggregationFlattener) table() *AggregationTable {
+	table := &AggregationTable{
+		Columns: this.columns,
+		Rows:    make([][]interface{}, 0, len(this.rows)),
+	}
+	if table.Columns == nil {
+		table.Columns = make([]string, 0)
+	}
+	for _, row := range this.rows {
+		if len(row) == 0 {
+			continue
+		}
+		values := make([]interface{}, len(this.columns))
+		for column, value := range row {
+			values[this.index[column]] = value
+		}
+		table.Rows = append(table.Rows, values)
+	}
+	return table
+}
+
+func bucketKey(bucket map[string]interface{}) interface{} {
+	if key, ok := bucket["key_as_string"]; ok {
+		return key
+	}
+	return bucket["key"]
+}
+
+func copyRow(row map[string]interface{}) map[string]interface{} {
+	ret := make(map[string]interface{}, len(row))
+	for k, v := range row {
+		ret[k] = v
+	}
+	return ret
+}
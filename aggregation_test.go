@@ -0,0 +1,127 @@
+package go_elasticsearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFlattenAggregations(t *testing.T) {
+	data := json.RawMessage(`{
+		"group_by_customer_name": {
+			"doc_count_error_upper_bound": 0,
+			"sum_other_doc_count": 0,
+			"buckets": [
+				{
+					"key": "acme",
+					"doc_count": 3,
+					"per_day": {
+						"buckets": [
+							{"key_as_string": "2020-03-07", "key": 1583539200000, "doc_count": 2, "carriage": {"value": 20.5}},
+							{"key_as_string": "2020-03-08", "key": 1583625600000, "doc_count": 1, "carriage": {"value": 7}}
+						]
+					}
+				},
+				{
+					"key": "globex",
+					"doc_count": 1,
+					"per_day": {
+						"buckets": [
+							{"key_as_string": "2020-03-07", "key": 1583539200000, "doc_count": 1, "carriage": {"value": 3}}
+						]
+					}
+				}
+			]
+		}
+	}`)
+	table, err := FlattenAggregations(data, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	columns := []string{"group_by_customer_name", "group_by_customer_name.doc_count", "per_day", "per_day.doc_count", "carriage"}
+	if !reflect.DeepEqual(table.Columns, columns) {
+		t.Fatalf("expected columns %v, got %v", columns, table.Columns)
+	}
+	if len(table.Rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(table.Rows))
+	}
+
+	var buf bytes.Buffer
+	if err := table.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := "group_by_customer_name,group_by_customer_name.doc_count,per_day,per_day.doc_count,carriage\n" +
+		"acme,3,2020-03-07,2,20.5\n" +
+		"acme,3,2020-03-08,1,7\n" +
+		"globex,1,2020-03-07,1,3\n"
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+}
+
+func TestFlattenAggregationsMetrics(t *testing.T) {
+	data := json.RawMessage(`{
+		"freight": {"count": 2, "min": 1, "max": 3, "avg": 2, "sum": 4},
+		"total": {"value": 4}
+	}`)
+	table, err := FlattenAggregations(data, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	columns := []string{"freight.avg", "freight.count", "freight.max", "freight.min", "freight.sum", "total"}
+	if !reflect.DeepEqual(table.Columns, columns) {
+		t.Fatalf("expected columns %v, got %v", columns, table.Columns)
+	}
+	if len(table.Rows) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(table.Rows))
+	}
+}
+
+func TestFlattenAggregationsEmptyBuckets(t *testing.T) {
+	data := json.RawMessage(`{
+		"group_by_customer_name": {
+			"buckets": [
+				{"key": "acme", "doc_count": 3, "per_day": {"buckets": []}},
+				{"key": "globex", "doc_count": 1, "per_day": {"buckets": [{"key_as_string": "2020-03-07", "key": 1583539200000, "doc_count": 1}]}}
+			]
+		}
+	}`)
+	table, err := FlattenAggregations(data, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := table.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := "group_by_customer_name,per_day\n" +
+		"acme,\n" +
+		"globex,2020-03-07\n"
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+}
+
+func TestFlattenAggregationsSiblingBuckets(t *testing.T) {
+	data := json.RawMessage(`{
+		"by_customer": {"buckets": [{"key": "acme", "doc_count": 3}, {"key": "globex", "doc_count": 1}]},
+		"by_status": {"buckets": [{"key": "open", "doc_count": 4}]},
+		"total": {"value": 4}
+	}`)
+	table, err := FlattenAggregations(data, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := table.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := "total,by_customer,by_customer.doc_count,by_status,by_status.doc_count\n" +
+		"4,acme,3,,\n" +
+		"4,globex,1,,\n" +
+		"4,,,open,4\n"
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+}
@@ -0,0 +1,48 @@
+package go_elasticsearch
+
+import "time"
+
+// Backoff decides how long to wait before retrying a failed request.
+type Backoff interface {
+	// Next returns the wait before the given retry, starting at 1, and
+	// false if no more retries should be made.
+	Next(retry int) (time.Duration, bool)
+}
+
+// ExponentialBackoff doubles the wait with every retry, starting at initial,
+// and gives up once the wait would exceed max.
+type ExponentialBackoff struct {
+	initial time.Duration
+	max     time.Duration
+}
+
+func NewExponentialBackoff(initial, max time.Duration) *ExponentialBackoff {
+	return &ExponentialBackoff{
+		initial: initial,
+		max:     max,
+	}
+}
+
+func (this *ExponentialBackoff) Next(retry int) (time.Duration, bool) {
+	if retry < 1 || this.initial <= 0 {
+		return 0, false
+	}
+	wait := this.initial
+	for i := 1; i < retry; i++ {
+		wait *= 2
+		if wait > this.max {
+			return 0, false
+		}
+	}
+	if wait > this.max {
+		return 0, false
+	}
+	return wait, true
+}
+
+// StopBackoff never retries.
+type StopBackoff struct{}
+
+func (StopBackoff) Next(retry int) (time.Duration, bool) {
+	return 0, false
+}
@@ -0,0 +1,302 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/wh5231/go-elasticsearch/uritemplates"
+	"net/http"
+	"net/url"
+)
+
+// BulkService executes index, create, update and delete actions in a single
+// round trip with the _bulk endpoint. Failed actions do not fail the
+// request; they are reported per item in the BulkResponse.
+//
+//	bulk := client.Bulk("md_fin_waybill").Add(
+//		NewBulkIndexRequest().Id("W1").Doc(waybill1),
+//		NewBulkDeleteRequest().Id("W2"),
+//	)
+//	response, err := bulk.Do(ctx)
+//	retry := response.FailedRequests(bulk.Requests())
+type BulkService struct {
+	client   *Client
+	index    string
+	typ      string
+	requests []BulkableRequest
+	refresh  string
+	routing  string
+	pipeline string
+	timeout  string
+}
+
+func NewBulkService(c *Client) *BulkService {
+	return &BulkService{
+		client:   c,
+		requests: make([]BulkableRequest, 0),
+	}
+}
+
+// Bulk returns a bulk service whose actions default to the given index.
+func (this *Client) Bulk(index ...string) *BulkService {
+	service := NewBulkService(this)
+	if len(index) > 0 {
+		service.Index(index[0])
+	}
+	return service
+}
+
+// Index sets the default index of the actions.
+func (this *BulkService) Index(index string) *BulkService {
+	this.index = index
+	return this
+}
+
+// Type sets the default type of the actions, for Elasticsearch 6.x.
+func (this *BulkService) Type(typ string) *BulkService {
+	this.typ = typ
+	return this
+}
+
+// Add adds actions, which are executed and reported in order.
+func (this *BulkService) Add(requests ...BulkableRequest) *BulkService {
+	this.requests = append(this.requests, requests...)
+	return this
+}
+
+// Refresh sets when the changes are made visible to search: "true",
+// "wait_for" or "false".
+func (this *BulkService) Refresh(refresh string) *BulkService {
+	this.refresh = refresh
+	return this
+}
+
+func (this *BulkService) Routing(routing string) *BulkService {
+	this.routing = routing
+	return this
+}
+
+func (this *BulkService) Pipeline(pipeline string) *BulkService {
+	this.pipeline = pipeline
+	return this
+}
+
+func (this *BulkService) Timeout(timeout string) *BulkService {
+	this.timeout = timeout
+	return this
+}
+
+// Requests returns the actions added, in order.
+func (this *BulkService) Requests() []BulkableRequest {
+	return this.requests
+}
+
+func (this *BulkService) NumberOfActions() int {
+	return len(this.requests)
+}
+
+// EstimatedSizeInBytes returns the size of the NDJSON body of the actions.
+func (this *BulkService) EstimatedSizeInBytes() (int64, error) {
+	var size int64
+	for _, request := range this.requests {
+		n, err := bulkRequestSize(request)
+		if err != nil {
+			return 0, err
+		}
+		size += n
+	}
+	return size, nil
+}
+
+// Reset removes all actions, so the service can be reused.
+func (this *BulkService) Reset() {
+	this.requests = make([]BulkableRequest, 0)
+}
+
+// Body returns the NDJSON body of the actions.
+func (this *BulkService) Body() (NDJSON, error) {
+	lines := make(NDJSON, 0, 2*len(this.requests))
+	for i, request := range this.requests {
+		source, err := request.Source()
+		if err != nil {
+			return nil, fmt.Errorf("bulk action %d: %v", i, err)
+		}
+		lines = append(lines, source...)
+	}
+	return lines, nil
+}
+
+func (this *BulkService) buildUrl() (string, url.Values, error) {
+	var (
+		err  error
+		path = "/_bulk"
+	)
+	if this.index != "" && this.typ != "" {
+		path, err = uritemplates.Expand("/{index}/{type}/_bulk", map[string]string{
+			"index": this.index,
+			"type":  this.typ,
+		})
+	} else if this.index != "" {
+		path, err = uritemplates.Expand("/{index}/_bulk", map[string]string{
+			"index": this.index,
+		})
+	}
+	if err != nil {
+		return "", url.Values{}, err
+	}
+	params := url.Values{}
+	if this.refresh != "" {
+		params.Set("refresh", this.refresh)
+	}
+	if this.routing != "" {
+		params.Set("routing", this.routing)
+	}
+	if this.pipeline != "" {
+		params.Set("pipeline", this.pipeline)
+	}
+	if this.timeout != "" {
+		params.Set("timeout", this.timeout)
+	}
+	return path, params, nil
+}
+
+// Do executes the actions. A non-2xx response is returned as *Error; check
+// BulkResponse.Errors for failed actions.
+func (this *BulkService) Do(ctx context.Context) (*BulkResponse, error) {
+	if len(this.requests) == 0 {
+		return nil, fmt.Errorf("bulk request has no actions")
+	}
+	body, err := this.Body()
+	if err != nil {
+		return nil, err
+	}
+	path, params, err := this.buildUrl()
+	if err != nil {
+		return nil, err
+	}
+	response, err := this.client.httpRequest(ctx, "POST", path, params, body, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(BulkResponse)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// BulkResponse is the response of a bulk request, with an item per action
+// in the order the actions were added.
+type BulkResponse struct {
+	Took   int64               `json:"took"`
+	Errors bool                `json:"errors"`
+	Items  []*BulkResponseItem `json:"items"`
+}
+
+// BulkResponseItem is the result of a bulk action. Error is set if the
+// action failed.
+type BulkResponseItem struct {
+	// Op is the action: "index", "create", "update" or "delete".
+	Op          string        `json:"-"`
+	Index       string        `json:"_index"`
+	Type        string        `json:"_type,omitempty"` // removed in Elasticsearch 8
+	Id          string        `json:"_id"`
+	Version     int64         `json:"_version,omitempty"`
+	Result      string        `json:"result,omitempty"`
+	Shards      *ShardsInfo   `json:"_shards,omitempty"`
+	SeqNo       int64         `json:"_seq_no,omitempty"`
+	PrimaryTerm int64         `json:"_primary_term,omitempty"`
+	Status      int           `json:"status"`
+	Error       *ErrorDetails `json:"error,omitempty"`
+	GetResult   *GetResult    `json:"get,omitempty"`
+}
+
+// UnmarshalJSON decodes an item of the form {"index": {...}}, setting Op to
+// the action.
+func (this *BulkResponseItem) UnmarshalJSON(data []byte) error {
+	type item BulkResponseItem
+	items := make(map[string]*item)
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	for op, result := range items {
+		if result != nil {
+			*this = BulkResponseItem(*result)
+		}
+		this.Op = op
+	}
+	return nil
+}
+
+// Failed returns whether the action failed.
+func (this *BulkResponseItem) Failed() bool {
+	return this.Error != nil || this.Status < 200 || this.Status > 299
+}
+
+// Retryable returns whether the action failed because Elasticsearch was
+// overloaded, e.g. with status 429 when its write queue is full, so that
+// it can succeed if retried later.
+func (this *BulkResponseItem) Retryable() bool {
+	switch this.Status {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return this.Error != nil && this.Error.Type == "es_rejected_execution_exception"
+}
+
+// Succeeded returns the items of the actions that succeeded.
+func (this *BulkResponse) Succeeded() []*BulkResponseItem {
+	items := make([]*BulkResponseItem, 0, len(this.Items))
+	for _, item := range this.Items {
+		if !item.Failed() {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Failed returns the items of the actions that failed.
+func (this *BulkResponse) Failed() []*BulkResponseItem {
+	items := make([]*BulkResponseItem, 0)
+	for _, item := range this.Items {
+		if item.Failed() {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// FailedRequests returns the requests whose actions failed, given the
+// requests of the bulk in order, e.g. BulkService.Requests.
+func (this *BulkResponse) FailedRequests(requests []BulkableRequest) []BulkableRequest {
+	failed := make([]BulkableRequest, 0)
+	for i, item := range this.Items {
+		if i < len(requests) && item.Failed() {
+			failed = append(failed, requests[i])
+		}
+	}
+	return failed
+}
+
+// SplitFailed splits the requests whose actions failed, given the requests
+// of the bulk in order, into those worth retrying (see
+// BulkResponseItem.Retryable) and those that will fail again, e.g. because
+// of a mapping error.
+func (this *BulkResponse) SplitFailed(requests []BulkableRequest) (retryable, permanent []BulkableRequest) {
+	retryable = make([]BulkableRequest, 0)
+	permanent = make([]BulkableRequest, 0)
+	for i, item := range this.Items {
+		if i >= len(requests) {
+			break
+		}
+		if !item.Failed() {
+			continue
+		}
+		if item.Retryable() {
+			retryable = append(retryable, requests[i])
+		} else {
+			permanent = append(permanent, requests[i])
+		}
+	}
+	return retryable, permanent
+}
@@ -0,0 +1,512 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BulkBeforeFunc is called before a bulk request is committed.
+type BulkBeforeFunc func(executionId int64, requests []BulkableRequest)
+
+// BulkAfterFunc is called after a bulk request was committed, including its
+// retries. The response holds the last item per request, in order; it is
+// nil if the bulk request never succeeded, and err is set if it failed as
+// a whole. If a retry failed as a whole, the response holds the items of the
+// earlier attempts, with the actions that were not committed marked failed.
+type BulkAfterFunc func(executionId int64, requests []BulkableRequest, response *BulkResponse, err error)
+
+// BulkProcessorService configures and starts a BulkProcessor.
+//
+//	processor, err := client.BulkProcessor().
+//		Workers(4).
+//		BulkActions(1000).
+//		BulkSize(5 << 20).
+//		FlushInterval(time.Second).
+//		After(func(id int64, requests []BulkableRequest, response *BulkResponse, err error) { ... }).
+//		Start(ctx)
+//	processor.Add(NewBulkIndexRequest().Index("md_fin_waybill").Id("W1").Doc(waybill))
+//	err = processor.Close(ctx)
+type BulkProcessorService struct {
+	client        *Client
+	index         string
+	workers       int
+	bulkActions   int
+	bulkSize      int64
+	flushInterval time.Duration
+	backoff       Backoff
+	before        BulkBeforeFunc
+	after         BulkAfterFunc
+}
+
+func NewBulkProcessorService(c *Client) *BulkProcessorService {
+	return &BulkProcessorService{
+		client:      c,
+		workers:     1,
+		bulkActions: 1000,
+		bulkSize:    5 << 20,
+		backoff:     NewExponentialBackoff(100*time.Millisecond, 8*time.Second),
+	}
+}
+
+func (this *Client) BulkProcessor() *BulkProcessorService {
+	return NewBulkProcessorService(this)
+}
+
+// Index sets the default index of the actions.
+func (this *BulkProcessorService) Index(index string) *BulkProcessorService {
+	this.index = index
+	return this
+}
+
+// Workers sets the number of bulk requests committed concurrently. The
+// default is 1. Every worker collects its own actions, so actions on the same
+// document, e.g. an index followed by a delete of the same _id, may be
+// committed by different workers and reach Elasticsearch in any order; use a
+// single worker when their order matters.
+func (this *BulkProcessorService) Workers(workers int) *BulkProcessorService {
+	this.workers = workers
+	return this
+}
+
+// BulkActions sets the number of actions per worker after which a bulk
+// request is committed, or -1 to disable. The default is 1000. As the
+// threshold applies to every worker, up to Workers times as many actions
+// may be pending.
+func (this *BulkProcessorService) BulkActions(bulkActions int) *BulkProcessorService {
+	this.bulkActions = bulkActions
+	return this
+}
+
+// BulkSize sets the size in bytes of the actions per worker after which a
+// bulk request is committed, or -1 to disable. The default is 5 MB. As the
+// threshold applies to every worker, up to Workers times as many bytes may
+// be pending.
+func (this *BulkProcessorService) BulkSize(bulkSize int64) *BulkProcessorService {
+	this.bulkSize = bulkSize
+	return this
+}
+
+// FlushInterval commits the pending actions of all workers periodically.
+// It is disabled by default.
+func (this *BulkProcessorService) FlushInterval(interval time.Duration) *BulkProcessorService {
+	this.flushInterval = interval
+	return this
+}
+
+// Backoff sets how failed bulk requests, and actions rejected with status
+// 429, are retried. The default is an exponential backoff from 100ms up to
+// 8s; use StopBackoff{} to disable retries.
+func (this *BulkProcessorService) Backoff(backoff Backoff) *BulkProcessorService {
+	this.backoff = backoff
+	return this
+}
+
+func (this *BulkProcessorService) Before(fn BulkBeforeFunc) *BulkProcessorService {
+	this.before = fn
+	return this
+}
+
+func (this *BulkProcessorService) After(fn BulkAfterFunc) *BulkProcessorService {
+	this.after = fn
+	return this
+}
+
+// Start starts the workers. The bulk requests are executed with ctx; cancel
+// it, or call Close, to stop the processor.
+func (this *BulkProcessorService) Start(ctx context.Context) (*BulkProcessor, error) {
+	workers := this.workers
+	if workers < 1 {
+		workers = 1
+	}
+	backoff := this.backoff
+	if backoff == nil {
+		backoff = StopBackoff{}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	p := &BulkProcessor{
+		client:        this.client,
+		index:         this.index,
+		bulkActions:   this.bulkActions,
+		bulkSize:      this.bulkSize,
+		flushInterval: this.flushInterval,
+		backoff:       backoff,
+		before:        this.before,
+		after:         this.after,
+		ctx:           ctx,
+		cancel:        cancel,
+		requestsC:     make(chan BulkableRequest),
+		stopC:         make(chan struct{}),
+		workers:       make([]*bulkWorker, 0, workers),
+	}
+	for i := 0; i < workers; i++ {
+		worker := newBulkWorker(p)
+		p.workers = append(p.workers, worker)
+		p.wg.Add(1)
+		go worker.work()
+	}
+	if p.flushInterval > 0 {
+		p.wg.Add(1)
+		go p.flusher()
+	}
+	return p, nil
+}
+
+// BulkProcessor commits actions in bulk requests in the background. Actions
+// are added with Add and committed by a pool of workers once a worker has
+// collected enough actions or bytes, periodically, on Flush and on Close.
+type BulkProcessor struct {
+	client        *Client
+	index         string
+	bulkActions   int
+	bulkSize      int64
+	flushInterval time.Duration
+	backoff       Backoff
+	before        BulkBeforeFunc
+	after         BulkAfterFunc
+
+	ctx         context.Context
+	cancel      context.CancelFunc
+	mu          sync.RWMutex
+	closed      bool
+	requestsC   chan BulkableRequest
+	stopC       chan struct{}
+	workers     []*bulkWorker
+	wg          sync.WaitGroup
+	executionId int64
+
+	statsMu sync.Mutex
+	stats   BulkProcessorStats
+}
+
+// BulkProcessorStats are the statistics of a BulkProcessor.
+type BulkProcessorStats struct {
+	Flushed   int64 // number of flushes by interval
+	Committed int64 // number of bulk requests committed, without retries
+	Indexed   int64 // number of documents indexed
+	Created   int64 // number of documents created
+	Updated   int64 // number of documents updated
+	Deleted   int64 // number of documents deleted
+	Succeeded int64 // number of actions that succeeded
+	Failed    int64 // number of actions that failed, after retries
+	Retried   int64 // number of actions retried
+}
+
+// Add adds actions. It blocks while all workers are busy committing. Adding
+// to a closed processor is an error.
+func (this *BulkProcessor) Add(requests ...BulkableRequest) error {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	if this.closed {
+		return fmt.Errorf("bulk processor is closed")
+	}
+	for _, request := range requests {
+		select {
+		case this.requestsC <- request:
+		case <-this.ctx.Done():
+			return this.ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Flush commits the pending actions of all workers and waits until they
+// are committed.
+func (this *BulkProcessor) Flush() error {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	if this.closed {
+		return fmt.Errorf("bulk processor is closed")
+	}
+	for _, worker := range this.workers {
+		select {
+		case worker.flushC <- struct{}{}:
+		case <-this.ctx.Done():
+			return this.ctx.Err()
+		}
+		<-worker.flushAckC
+	}
+	return nil
+}
+
+// Close stops accepting actions, commits the pending ones and waits for
+// the workers to finish. If ctx is done first, the outstanding bulk requests
+// are canceled and ctx.Err() is returned.
+func (this *BulkProcessor) Close(ctx context.Context) error {
+	this.mu.Lock()
+	if this.closed {
+		this.mu.Unlock()
+		return nil
+	}
+	this.closed = true
+	close(this.requestsC)
+	close(this.stopC)
+	this.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		this.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		this.cancel()
+		return nil
+	case <-ctx.Done():
+		this.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// Stats returns a snapshot of the statistics.
+func (this *BulkProcessor) Stats() BulkProcessorStats {
+	this.statsMu.Lock()
+	defer this.statsMu.Unlock()
+	return this.stats
+}
+
+// flusher flushes the workers every flush interval until the processor is
+// closed.
+func (this *BulkProcessor) flusher() {
+	defer this.wg.Done()
+	ticker := time.NewTicker(this.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := this.Flush(); err == nil {
+				this.statsMu.Lock()
+				this.stats.Flushed++
+				this.statsMu.Unlock()
+			}
+		case <-this.stopC:
+			return
+		case <-this.ctx.Done():
+			return
+		}
+	}
+}
+
+// execute commits requests, retrying the whole bulk request if it fails with
+// a retryable error, and the actions rejected as retryable, until the
+// backoff gives up.
+func (this *BulkProcessor) execute(requests []BulkableRequest) (*BulkResponse, error) {
+	// merged holds the latest item per request, once a bulk request succeeded
+	var merged *BulkResponse
+	// positions of the pending requests in requests
+	pending := make([]int, len(requests))
+	for i := range pending {
+		pending[i] = i
+	}
+	for retry := 1; ; retry++ {
+		bulk := NewBulkService(this.client).Index(this.index)
+		for _, i := range pending {
+			bulk.Add(requests[i])
+		}
+		response, err := bulk.Do(this.ctx)
+		if err == nil && len(response.Items) != len(pending) {
+			return failPending(merged, pending, fmt.Errorf("bulk response has %d items for %d actions", len(response.Items), len(pending)))
+		}
+		if err != nil && !isRetryableBulkError(err) {
+			return failPending(merged, pending, err)
+		}
+
+		next := pending
+		if err == nil {
+			if merged == nil {
+				merged = &BulkResponse{Items: make([]*BulkResponseItem, len(requests))}
+			}
+			merged.Took += response.Took
+			next = make([]int, 0)
+			for j, item := range response.Items {
+				merged.Items[pending[j]] = item
+				if item.Failed() && item.Retryable() {
+					next = append(next, pending[j])
+				}
+			}
+			if len(next) == 0 {
+				break
+			}
+		}
+		wait, ok := this.backoff.Next(retry)
+		if !ok {
+			if err != nil {
+				return failPending(merged, pending, err)
+			}
+			break
+		}
+		this.statsMu.Lock()
+		this.stats.Retried += int64(len(next))
+		this.statsMu.Unlock()
+		select {
+		case <-time.After(wait):
+		case <-this.ctx.Done():
+			return failPending(merged, next, this.ctx.Err())
+		}
+		pending = next
+	}
+	for _, item := range merged.Items {
+		if item.Failed() {
+			merged.Errors = true
+		}
+	}
+	return merged, nil
+}
+
+// failPending returns merged, if a bulk request succeeded before, along with
+// err. The pending requests, which were not committed, keep the failed item
+// of their last attempt, or get one holding err if they have none.
+func failPending(merged *BulkResponse, pending []int, err error) (*BulkResponse, error) {
+	if merged == nil {
+		return nil, err
+	}
+	for _, i := range pending {
+		if item := merged.Items[i]; item == nil || !item.Failed() {
+			merged.Items[i] = &BulkResponseItem{Error: &ErrorDetails{Reason: err.Error()}}
+		}
+	}
+	merged.Errors = true
+	return merged, err
+}
+
+// isRetryableBulkError returns whether a failed bulk request may succeed if
+// retried: Elasticsearch is overloaded or unavailable, or the connection
+// failed.
+func isRetryableBulkError(err error) bool {
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return false
+	}
+	e, ok := err.(*Error)
+	if !ok {
+		return true
+	}
+	switch e.Status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// commit commits requests and reports the result to the after hook and the
+// statistics.
+func (this *BulkProcessor) commit(requests []BulkableRequest) {
+	if len(requests) == 0 {
+		return
+	}
+	id := atomic.AddInt64(&this.executionId, 1)
+	if this.before != nil {
+		this.before(id, requests)
+	}
+	response, err := this.execute(requests)
+	if this.after != nil {
+		this.after(id, requests, response, err)
+	}
+	this.record(requests, response)
+}
+
+// record updates the statistics with the result of a bulk request.
+func (this *BulkProcessor) record(requests []BulkableRequest, response *BulkResponse) {
+	this.statsMu.Lock()
+	defer this.statsMu.Unlock()
+	this.stats.Committed++
+	if response == nil {
+		this.stats.Failed += int64(len(requests))
+		return
+	}
+	for _, item := range response.Items {
+		if item.Failed() {
+			this.stats.Failed++
+			continue
+		}
+		this.stats.Succeeded++
+		switch item.Op {
+		case "index":
+			this.stats.Indexed++
+		case "create":
+			this.stats.Created++
+		case "update":
+			this.stats.Updated++
+		case "delete":
+			this.stats.Deleted++
+		}
+	}
+}
+
+// bulkWorker collects actions and commits them once enough are pending or
+// when asked to flush.
+type bulkWorker struct {
+	p         *BulkProcessor
+	requests  []BulkableRequest
+	size      int64
+	flushC    chan struct{}
+	flushAckC chan struct{}
+}
+
+func newBulkWorker(p *BulkProcessor) *bulkWorker {
+	return &bulkWorker{
+		p:         p,
+		requests:  make([]BulkableRequest, 0),
+		flushC:    make(chan struct{}),
+		flushAckC: make(chan struct{}),
+	}
+}
+
+func (this *bulkWorker) work() {
+	defer this.p.wg.Done()
+	for {
+		select {
+		case request, open := <-this.p.requestsC:
+			if !open {
+				this.commit()
+				return
+			}
+			size, err := bulkRequestSize(request)
+			if err != nil {
+				this.reject(request, err)
+				continue
+			}
+			this.requests = append(this.requests, request)
+			this.size += size
+			if this.commitRequired() {
+				this.commit()
+			}
+		case <-this.flushC:
+			this.commit()
+			this.flushAckC <- struct{}{}
+		}
+	}
+}
+
+func (this *bulkWorker) commitRequired() bool {
+	if this.p.bulkActions > 0 && len(this.requests) >= this.p.bulkActions {
+		return true
+	}
+	if this.p.bulkSize > 0 && this.size >= this.p.bulkSize {
+		return true
+	}
+	return false
+}
+
+func (this *bulkWorker) commit() {
+	requests := this.requests
+	this.requests = make([]BulkableRequest, 0)
+	this.size = 0
+	this.p.commit(requests)
+}
+
+// reject reports an action that cannot be encoded to the after hook without
+// sending it.
+func (this *bulkWorker) reject(request BulkableRequest, err error) {
+	requests := []BulkableRequest{request}
+	id := atomic.AddInt64(&this.p.executionId, 1)
+	if this.p.after != nil {
+		this.p.after(id, requests, nil, err)
+	}
+	this.p.statsMu.Lock()
+	this.p.stats.Failed++
+	this.p.statsMu.Unlock()
+}
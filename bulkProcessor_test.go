@@ -0,0 +1,180 @@
+package go_elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// writeBulkItems responds to a bulk request of index actions with an item
+// per action, using status(id) as the status of each.
+func writeBulkItems(w http.ResponseWriter, r *http.Request, status func(id string) int) int {
+	body, _ := ioutil.ReadAll(r.Body)
+	lines := bytes.Split(bytes.TrimSpace(body), []byte("\n"))
+	items := make([]string, 0)
+	for i := 0; i < len(lines); i += 2 {
+		var id string
+		fmt.Sscanf(string(lines[i]), `{"index":{"_id":%q}}`, &id)
+		if s := status(id); s == http.StatusTooManyRequests {
+			items = append(items, fmt.Sprintf(`{"index": {"_id": %q, "status": 429, "error": {"type": "es_rejected_execution_exception", "reason": "rejected"}}}`, id))
+		} else {
+			items = append(items, fmt.Sprintf(`{"index": {"_id": %q, "result": "created", "status": %d}}`, id, s))
+		}
+	}
+	fmt.Fprintf(w, `{"took": 1, "errors": false, "items": [%s]}`, strings.Join(items, ","))
+	return len(items)
+}
+
+func TestBulkProcessorFlushByActions(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		sizes []int
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := writeBulkItems(w, r, func(string) int { return http.StatusCreated })
+		mu.Lock()
+		sizes = append(sizes, n)
+		mu.Unlock()
+	})
+	processor, err := client.BulkProcessor().Index("md_fin_waybill").BulkActions(2).Start(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := processor.Add(NewBulkIndexRequest().Id(fmt.Sprintf("W%d", i)).Doc(map[string]interface{}{"F_OrderNo": i})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := processor.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(sizes) != "[2 2 1]" {
+		t.Errorf("expected bulk requests of 2, 2 and 1 actions, got %v", sizes)
+	}
+	stats := processor.Stats()
+	if stats.Committed != 3 || stats.Succeeded != 5 || stats.Indexed != 5 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if err := processor.Add(NewBulkIndexRequest().Id("W5").Doc(map[string]interface{}{})); err == nil {
+		t.Error("expected error adding to a closed processor")
+	}
+}
+
+func TestBulkProcessorRetriesRejectedItems(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts = make(map[string]int)
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		writeBulkItems(w, r, func(id string) int {
+			attempts[id]++
+			if id == "W1" && attempts[id] < 3 {
+				return http.StatusTooManyRequests
+			}
+			return http.StatusCreated
+		})
+	})
+	var after *BulkResponse
+	processor, err := client.BulkProcessor().
+		Index("md_fin_waybill").
+		Backoff(NewExponentialBackoff(time.Millisecond, 10*time.Millisecond)).
+		After(func(id int64, requests []BulkableRequest, response *BulkResponse, err error) {
+			if err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+			after = response
+		}).
+		Start(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	processor.Add(
+		NewBulkIndexRequest().Id("W0").Doc(map[string]interface{}{}),
+		NewBulkIndexRequest().Id("W1").Doc(map[string]interface{}{}),
+	)
+	if err := processor.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if after == nil || after.Errors || len(after.Items) != 2 || after.Items[1].Status != http.StatusCreated {
+		t.Errorf("expected both items to succeed, got %+v", after)
+	}
+	if attempts["W0"] != 1 || attempts["W1"] != 3 {
+		t.Errorf("expected W0 sent once and W1 three times, got %v", attempts)
+	}
+	if stats := processor.Stats(); stats.Retried != 2 || stats.Succeeded != 2 || stats.Failed != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	processor.Close(context.Background())
+}
+
+func TestBulkProcessorFailsPendingItems(t *testing.T) {
+	attempt := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempt++
+		if attempt > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error": {"type": "unavailable"}, "status": 503}`))
+			return
+		}
+		writeBulkItems(w, r, func(id string) int {
+			if id == "W1" {
+				return http.StatusTooManyRequests
+			}
+			return http.StatusCreated
+		})
+	})
+	var (
+		after    *BulkResponse
+		afterErr error
+	)
+	processor, err := client.BulkProcessor().
+		Index("md_fin_waybill").
+		// a single retry
+		Backoff(NewExponentialBackoff(time.Millisecond, time.Millisecond)).
+		After(func(id int64, requests []BulkableRequest, response *BulkResponse, err error) {
+			after, afterErr = response, err
+		}).
+		Start(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	processor.Add(
+		NewBulkIndexRequest().Id("W0").Doc(map[string]interface{}{}),
+		NewBulkIndexRequest().Id("W1").Doc(map[string]interface{}{}),
+	)
+	if err := processor.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if afterErr == nil || attempt != 2 {
+		t.Fatalf("expected the retry to fail after 2 attempts, got %v after %d", afterErr, attempt)
+	}
+	if after == nil || !after.Errors || after.Items[0].Failed() || !after.Items[1].Failed() {
+		t.Errorf("expected W0 to succeed and W1 to fail, got %+v", after)
+	}
+	if stats := processor.Stats(); stats.Succeeded != 1 || stats.Failed != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	processor.Close(context.Background())
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := NewExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	expected := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond}
+	for i, wait := range expected {
+		got, ok := backoff.Next(i + 1)
+		if !ok || got != wait {
+			t.Errorf("retry %d: expected %v, got %v (%v)", i+1, wait, got, ok)
+		}
+	}
+	if _, ok := backoff.Next(4); ok {
+		t.Error("expected the backoff to give up")
+	}
+}
@@ -0,0 +1,392 @@
+package go_elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// BulkableRequest is an action of a bulk request: index, create, update or
+// delete.
+type BulkableRequest interface {
+	// Source returns the NDJSON lines of the action: the action and its
+	// metadata, followed by the document for index, create and update.
+	Source() ([]interface{}, error)
+}
+
+// bulkRequestSize returns the size in bytes of the NDJSON lines of request.
+func bulkRequestSize(request BulkableRequest) (int64, error) {
+	lines, err := request.Source()
+	if err != nil {
+		return 0, err
+	}
+	var size int64
+	for _, line := range lines {
+		data, err := json.Marshal(line)
+		if err != nil {
+			return 0, err
+		}
+		size += int64(len(data)) + 1
+	}
+	return size, nil
+}
+
+// -- index, create --
+
+// BulkIndexRequest adds or replaces a document, or only adds it with
+// OpType("create").
+//
+//	NewBulkIndexRequest().Index("md_fin_waybill").Id("W123").Doc(waybill)
+type BulkIndexRequest struct {
+	index         string
+	typ           string
+	id            string
+	opType        string
+	routing       string
+	pipeline      string
+	version       *int64
+	versionType   string
+	ifSeqNo       *int64
+	ifPrimaryTerm *int64
+	doc           interface{}
+}
+
+func NewBulkIndexRequest() *BulkIndexRequest {
+	return &BulkIndexRequest{
+		opType: "index",
+	}
+}
+
+// NewBulkCreateRequest returns an index request failing if the document
+// exists already.
+func NewBulkCreateRequest() *BulkIndexRequest {
+	return NewBulkIndexRequest().OpType("create")
+}
+
+// Index sets the index of the document; it defaults to the index of the
+// BulkService.
+func (this *BulkIndexRequest) Index(index string) *BulkIndexRequest {
+	this.index = index
+	return this
+}
+
+// Type sets the type of the document, for Elasticsearch 6.x.
+func (this *BulkIndexRequest) Type(typ string) *BulkIndexRequest {
+	this.typ = typ
+	return this
+}
+
+// Id sets the id of the document. Without id, Elasticsearch generates one.
+func (this *BulkIndexRequest) Id(id string) *BulkIndexRequest {
+	this.id = id
+	return this
+}
+
+// OpType sets "index" or "create".
+func (this *BulkIndexRequest) OpType(opType string) *BulkIndexRequest {
+	this.opType = opType
+	return this
+}
+
+func (this *BulkIndexRequest) Routing(routing string) *BulkIndexRequest {
+	this.routing = routing
+	return this
+}
+
+func (this *BulkIndexRequest) Pipeline(pipeline string) *BulkIndexRequest {
+	this.pipeline = pipeline
+	return this
+}
+
+func (this *BulkIndexRequest) Version(version int64) *BulkIndexRequest {
+	this.version = &version
+	return this
+}
+
+func (this *BulkIndexRequest) VersionType(versionType string) *BulkIndexRequest {
+	this.versionType = versionType
+	return this
+}
+
+func (this *BulkIndexRequest) IfSeqNo(seqNo int64) *BulkIndexRequest {
+	this.ifSeqNo = &seqNo
+	return this
+}
+
+func (this *BulkIndexRequest) IfPrimaryTerm(primaryTerm int64) *BulkIndexRequest {
+	this.ifPrimaryTerm = &primaryTerm
+	return this
+}
+
+// Doc sets the document, encoded with json.Marshal; pass a json.RawMessage
+// for a document that is JSON already.
+func (this *BulkIndexRequest) Doc(doc interface{}) *BulkIndexRequest {
+	this.doc = doc
+	return this
+}
+
+func (this *BulkIndexRequest) Source() ([]interface{}, error) {
+	if this.doc == nil {
+		return nil, fmt.Errorf("bulk %s request requires a doc", this.opType)
+	}
+	meta := make(map[string]interface{})
+	if this.index != "" {
+		meta["_index"] = this.index
+	}
+	if this.typ != "" {
+		meta["_type"] = this.typ
+	}
+	if this.id != "" {
+		meta["_id"] = this.id
+	}
+	if this.routing != "" {
+		meta["routing"] = this.routing
+	}
+	if this.pipeline != "" {
+		meta["pipeline"] = this.pipeline
+	}
+	if this.version != nil {
+		meta["version"] = *this.version
+	}
+	if this.versionType != "" {
+		meta["version_type"] = this.versionType
+	}
+	if this.ifSeqNo != nil {
+		meta["if_seq_no"] = *this.ifSeqNo
+	}
+	if this.ifPrimaryTerm != nil {
+		meta["if_primary_term"] = *this.ifPrimaryTerm
+	}
+	return []interface{}{map[string]interface{}{this.opType: meta}, this.doc}, nil
+}
+
+// -- update --
+
+// BulkUpdateRequest updates a document with a partial document or a script,
+// see UpdateService.
+type BulkUpdateRequest struct {
+	index           string
+	typ             string
+	id              string
+	routing         string
+	retryOnConflict int
+	ifSeqNo         *int64
+	ifPrimaryTerm   *int64
+	doc             interface{}
+	docAsUpsert     bool
+	upsert          interface{}
+	script          interface{}
+	scriptedUpsert  bool
+	detectNoop      *bool
+}
+
+func NewBulkUpdateRequest() *BulkUpdateRequest {
+	return &BulkUpdateRequest{}
+}
+
+// Index sets the index of the document; it defaults to the index of the
+// BulkService.
+func (this *BulkUpdateRequest) Index(index string) *BulkUpdateRequest {
+	this.index = index
+	return this
+}
+
+// Type sets the type of the document, for Elasticsearch 6.x.
+func (this *BulkUpdateRequest) Type(typ string) *BulkUpdateRequest {
+	this.typ = typ
+	return this
+}
+
+func (this *BulkUpdateRequest) Id(id string) *BulkUpdateRequest {
+	this.id = id
+	return this
+}
+
+func (this *BulkUpdateRequest) Routing(routing string) *BulkUpdateRequest {
+	this.routing = routing
+	return this
+}
+
+func (this *BulkUpdateRequest) RetryOnConflict(n int) *BulkUpdateRequest {
+	this.retryOnConflict = n
+	return this
+}
+
+func (this *BulkUpdateRequest) IfSeqNo(seqNo int64) *BulkUpdateRequest {
+	this.ifSeqNo = &seqNo
+	return this
+}
+
+func (this *BulkUpdateRequest) IfPrimaryTerm(primaryTerm int64) *BulkUpdateRequest {
+	this.ifPrimaryTerm = &primaryTerm
+	return this
+}
+
+// Doc sets the partial document merged into the document.
+func (this *BulkUpdateRequest) Doc(doc interface{}) *BulkUpdateRequest {
+	this.doc = doc
+	return this
+}
+
+func (this *BulkUpdateRequest) DocAsUpsert(docAsUpsert bool) *BulkUpdateRequest {
+	this.docAsUpsert = docAsUpsert
+	return this
+}
+
+func (this *BulkUpdateRequest) Upsert(upsert interface{}) *BulkUpdateRequest {
+	this.upsert = upsert
+	return this
+}
+
+// Script sets the script updating the document, see UpdateService.Script.
+func (this *BulkUpdateRequest) Script(script interface{}) *BulkUpdateRequest {
+	this.script = script
+	return this
+}
+
+func (this *BulkUpdateRequest) ScriptedUpsert(scriptedUpsert bool) *BulkUpdateRequest {
+	this.scriptedUpsert = scriptedUpsert
+	return this
+}
+
+func (this *BulkUpdateRequest) DetectNoop(detectNoop bool) *BulkUpdateRequest {
+	this.detectNoop = &detectNoop
+	return this
+}
+
+func (this *BulkUpdateRequest) Source() ([]interface{}, error) {
+	if this.id == "" {
+		return nil, fmt.Errorf("bulk update request requires an id")
+	}
+	if this.doc == nil && this.script == nil {
+		return nil, fmt.Errorf("bulk update request requires a doc or a script")
+	}
+	meta := map[string]interface{}{"_id": this.id}
+	if this.index != "" {
+		meta["_index"] = this.index
+	}
+	if this.typ != "" {
+		meta["_type"] = this.typ
+	}
+	if this.routing != "" {
+		meta["routing"] = this.routing
+	}
+	if this.retryOnConflict > 0 {
+		meta["retry_on_conflict"] = this.retryOnConflict
+	}
+	if this.ifSeqNo != nil {
+		meta["if_seq_no"] = *this.ifSeqNo
+	}
+	if this.ifPrimaryTerm != nil {
+		meta["if_primary_term"] = *this.ifPrimaryTerm
+	}
+	body := make(map[string]interface{})
+	if this.doc != nil {
+		body["doc"] = this.doc
+	}
+	if this.docAsUpsert {
+		body["doc_as_upsert"] = true
+	}
+	if this.upsert != nil {
+		body["upsert"] = this.upsert
+	}
+	if this.script != nil {
+		body["script"] = this.script
+	}
+	if this.scriptedUpsert {
+		body["scripted_upsert"] = true
+	}
+	if this.detectNoop != nil {
+		body["detect_noop"] = *this.detectNoop
+	}
+	return []interface{}{map[string]interface{}{"update": meta}, body}, nil
+}
+
+// -- delete --
+
+// BulkDeleteRequest deletes a document.
+type BulkDeleteRequest struct {
+	index         string
+	typ           string
+	id            string
+	routing       string
+	version       *int64
+	versionType   string
+	ifSeqNo       *int64
+	ifPrimaryTerm *int64
+}
+
+func NewBulkDeleteRequest() *BulkDeleteRequest {
+	return &BulkDeleteRequest{}
+}
+
+// Index sets the index of the document; it defaults to the index of the
+// BulkService.
+func (this *BulkDeleteRequest) Index(index string) *BulkDeleteRequest {
+	this.index = index
+	return this
+}
+
+// Type sets the type of the document, for Elasticsearch 6.x.
+func (this *BulkDeleteRequest) Type(typ string) *BulkDeleteRequest {
+	this.typ = typ
+	return this
+}
+
+func (this *BulkDeleteRequest) Id(id string) *BulkDeleteRequest {
+	this.id = id
+	return this
+}
+
+func (this *BulkDeleteRequest) Routing(routing string) *BulkDeleteRequest {
+	this.routing = routing
+	return this
+}
+
+func (this *BulkDeleteRequest) Version(version int64) *BulkDeleteRequest {
+	this.version = &version
+	return this
+}
+
+func (this *BulkDeleteRequest) VersionType(versionType string) *BulkDeleteRequest {
+	this.versionType = versionType
+	return this
+}
+
+func (this *BulkDeleteRequest) IfSeqNo(seqNo int64) *BulkDeleteRequest {
+	this.ifSeqNo = &seqNo
+	return this
+}
+
+func (this *BulkDeleteRequest) IfPrimaryTerm(primaryTerm int64) *BulkDeleteRequest {
+	this.ifPrimaryTerm = &primaryTerm
+	return this
+}
+
+func (this *BulkDeleteRequest) Source() ([]interface{}, error) {
+	if this.id == "" {
+		return nil, fmt.Errorf("bulk delete request requires an id")
+	}
+	meta := map[string]interface{}{"_id": this.id}
+	if this.index != "" {
+		meta["_index"] = this.index
+	}
+	if this.typ != "" {
+		meta["_type"] = this.typ
+	}
+	if this.routing != "" {
+		meta["routing"] = this.routing
+	}
+	if this.version != nil {
+		meta["version"] = *this.version
+	}
+	if this.versionType != "" {
+		meta["version_type"] = this.versionType
+	}
+	if this.ifSeqNo != nil {
+		meta["if_seq_no"] = *this.ifSeqNo
+	}
+	if this.ifPrimaryTerm != nil {
+		meta["if_primary_term"] = *this.ifPrimaryTerm
+	}
+	return []interface{}{map[string]interface{}{"delete": meta}}, nil
+}
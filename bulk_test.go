@@ -0,0 +1,87 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestBulk(t *testing.T) {
+	expected := `{"index":{"_id":"W1"}}` + "\n" +
+		`{"F_OrderNo":"O1"}` + "\n" +
+		`{"create":{"_id":"W2","_index":"md_fin_waybill_2020"}}` + "\n" +
+		`{"F_OrderNo":"O2"}` + "\n" +
+		`{"update":{"_id":"W3","retry_on_conflict":3}}` + "\n" +
+		`{"doc":{"F_FJScan_Flag":1},"doc_as_upsert":true}` + "\n" +
+		`{"delete":{"_id":"W4","routing":"c1"}}` + "\n"
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/md_fin_waybill/_bulk" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+			t.Errorf("expected NDJSON content type, got %q", ct)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != expected {
+			t.Errorf("expected body\n%s\ngot\n%s", expected, string(body))
+		}
+		w.Write([]byte(`{"took": 30, "errors": true, "items": [
+			{"index": {"_index": "md_fin_waybill", "_id": "W1", "result": "created", "status": 201}},
+			{"create": {"_index": "md_fin_waybill_2020", "_id": "W2", "status": 409,
+				"error": {"type": "version_conflict_engine_exception", "reason": "[W2]: document already exists"}}},
+			{"update": {"_index": "md_fin_waybill", "_id": "W3", "status": 429,
+				"error": {"type": "es_rejected_execution_exception", "reason": "rejected execution"}}},
+			{"delete": {"_index": "md_fin_waybill", "_id": "W4", "result": "deleted", "status": 200}}
+		]}`))
+	})
+
+	bulk := client.Bulk("md_fin_waybill").Add(
+		NewBulkIndexRequest().Id("W1").Doc(map[string]interface{}{"F_OrderNo": "O1"}),
+		NewBulkCreateRequest().Index("md_fin_waybill_2020").Id("W2").Doc(json.RawMessage(`{"F_OrderNo":"O2"}`)),
+		NewBulkUpdateRequest().Id("W3").Doc(map[string]interface{}{"F_FJScan_Flag": 1}).DocAsUpsert(true).RetryOnConflict(3),
+		NewBulkDeleteRequest().Id("W4").Routing("c1"),
+	)
+	size, err := bulk.EstimatedSizeInBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != int64(len(expected)) {
+		t.Errorf("expected size %d, got %d", len(expected), size)
+	}
+	response, err := bulk.Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !response.Errors || len(response.Items) != 4 {
+		t.Fatalf("unexpected response %+v", response)
+	}
+	if response.Items[1].Op != "create" || response.Items[1].Error == nil {
+		t.Errorf("unexpected item %+v", response.Items[1])
+	}
+	if len(response.Succeeded()) != 2 || len(response.Failed()) != 2 {
+		t.Errorf("expected 2 succeeded and 2 failed items, got %d and %d", len(response.Succeeded()), len(response.Failed()))
+	}
+	retryable, permanent := response.SplitFailed(bulk.Requests())
+	if len(retryable) != 1 || retryable[0] != bulk.Requests()[2] {
+		t.Errorf("expected the update to be retryable, got %v", retryable)
+	}
+	if len(permanent) != 1 || permanent[0] != bulk.Requests()[1] {
+		t.Errorf("expected the create to fail permanently, got %v", permanent)
+	}
+}
+
+func TestBulkRequestValidation(t *testing.T) {
+	requests := []BulkableRequest{
+		NewBulkIndexRequest().Id("W1"),
+		NewBulkUpdateRequest().Doc(map[string]interface{}{}),
+		NewBulkUpdateRequest().Id("W1"),
+		NewBulkDeleteRequest(),
+	}
+	for i, request := range requests {
+		if _, err := request.Source(); err == nil {
+			t.Errorf("test %d: expected error", i)
+		}
+	}
+}
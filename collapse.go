@@ -0,0 +1,135 @@
+package go_elasticsearch
+
+import "fmt"
+
+// Collapse collapses the hits of a search on the values of a field, e.g. to
+// return the best dispatch candidate per carrier. Inner hits return more
+// hits per collapsed value.
+//
+//	client.Search("md_dispatch").Collapse(
+//		NewCollapse("F_CarrierId").InnerHit(NewInnerHit("latest").Size(3).OrderBy(map[string]string{"F_OrderTime": "desc"})),
+//	)
+type Collapse struct {
+	field                      string
+	innerHits                  []*InnerHit
+	maxConcurrentGroupSearches int
+}
+
+func NewCollapse(field string) *Collapse {
+	return &Collapse{
+		field:     field,
+		innerHits: make([]*InnerHit, 0),
+	}
+}
+
+// InnerHit adds inner hits, returned in SearchHit.InnerHits by name.
+func (this *Collapse) InnerHit(innerHits ...*InnerHit) *Collapse {
+	this.innerHits = append(this.innerHits, innerHits...)
+	return this
+}
+
+func (this *Collapse) MaxConcurrentGroupSearches(max int) *Collapse {
+	this.maxConcurrentGroupSearches = max
+	return this
+}
+
+func (this *Collapse) Source() (interface{}, error) {
+	if this.field == "" {
+		return nil, fmt.Errorf("collapse requires a field")
+	}
+	source := map[string]interface{}{"field": this.field}
+	switch len(this.innerHits) {
+	case 0:
+	case 1:
+		innerHit, err := this.innerHits[0].Source()
+		if err != nil {
+			return nil, err
+		}
+		source["inner_hits"] = innerHit
+	default:
+		innerHits := make([]interface{}, 0, len(this.innerHits))
+		for _, innerHit := range this.innerHits {
+			src, err := innerHit.Source()
+			if err != nil {
+				return nil, err
+			}
+			innerHits = append(innerHits, src)
+		}
+		source["inner_hits"] = innerHits
+	}
+	if this.maxConcurrentGroupSearches > 0 {
+		source["max_concurrent_group_searches"] = this.maxConcurrentGroupSearches
+	}
+	return source, nil
+}
+
+// InnerHit configures the inner hits returned per collapsed value.
+type InnerHit struct {
+	name     string
+	from     int
+	size     *int
+	orderBy  []map[string]string
+	source   interface{}
+	collapse *Collapse
+}
+
+func NewInnerHit(name string) *InnerHit {
+	return &InnerHit{
+		name:    name,
+		orderBy: make([]map[string]string, 0),
+	}
+}
+
+func (this *InnerHit) From(from int) *InnerHit {
+	this.from = from
+	return this
+}
+
+func (this *InnerHit) Size(size int) *InnerHit {
+	this.size = &size
+	return this
+}
+
+func (this *InnerHit) OrderBy(orderBy ...map[string]string) *InnerHit {
+	this.orderBy = append(this.orderBy, orderBy...)
+	return this
+}
+
+// FetchSource sets the _source option of the inner hits, see Query.Source.
+func (this *InnerHit) FetchSource(source interface{}) *InnerHit {
+	this.source = source
+	return this
+}
+
+// Collapse collapses the inner hits on a second field.
+func (this *InnerHit) Collapse(collapse *Collapse) *InnerHit {
+	this.collapse = collapse
+	return this
+}
+
+func (this *InnerHit) Source() (interface{}, error) {
+	if this.name == "" {
+		return nil, fmt.Errorf("inner hits require a name")
+	}
+	source := map[string]interface{}{"name": this.name}
+	if this.from > 0 {
+		source["from"] = this.from
+	}
+	if this.size != nil {
+		source["size"] = *this.size
+	}
+	if len(this.orderBy) > 0 {
+		source["sort"] = this.orderBy
+	}
+	if this.source != nil {
+		source["_source"] = this.source
+	}
+	if this.collapse != nil {
+		collapse, err := this.collapse.Source()
+		if err != nil {
+			return nil, err
+		}
+		source["collapse"] = collapse
+	}
+	return source, nil
+}
@@ -0,0 +1,247 @@
+package go_elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// CompositeBucket is a single bucket of a composite aggregation.
+type CompositeBucket struct {
+	Key      map[string]interface{}
+	DocCount int64
+	// Aggregations holds the raw sub-aggregations of the bucket, keyed by name.
+	Aggregations map[string]json.RawMessage
+}
+
+// CompositeIterator pages through all buckets of a composite aggregation by
+// re-issuing the search with the "after" key of the previous page. The pages
+// are searched with size 0 on a copy of the query, which is left untouched.
+//
+//	query := client.Search("md_fin_waybill").AddAgg("by_customer", "composite", map[string]interface{}{
+//		"size":    1000,
+//		"sources": []interface{}{map[string]interface{}{"customer": map[string]interface{}{"terms": map[string]string{"field": "F_O_CustomerName.keyword"}}}},
+//		"aggregations": map[string]interface{}{"carriage": map[string]interface{}{"sum": map[string]string{"field": "F_Freight"}}},
+//	})
+//	err := NewCompositeIterator(query, "by_customer").Each(ctx, func(bucket *CompositeBucket) error { ... })
+type CompositeIterator struct {
+	query *Query
+	name  string
+	after map[string]interface{}
+	done  bool
+}
+
+func NewCompositeIterator(query *Query, name string) *CompositeIterator {
+	return &CompositeIterator{
+		query: query,
+		name:  name,
+	}
+}
+
+// After sets the key to resume iterating after, e.g. a key returned by a
+// previous run.
+func (this *CompositeIterator) After(key map[string]interface{}) *CompositeIterator {
+	this.after = key
+	return this
+}
+
+// AfterKey returns the key of the last page returned by Next.
+func (this *CompositeIterator) AfterKey() map[string]interface{} {
+	return this.after
+}
+
+// Next returns the next page of buckets. It returns io.EOF when all buckets
+// have been consumed.
+func (this *CompositeIterator) Next(ctx context.Context) ([]*CompositeBucket, error) {
+	raw, err := this.next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	buckets := make([]*CompositeBucket, 0, len(raw))
+	for _, data := range raw {
+		bucket, err := decodeCompositeBucket(data)
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, nil
+}
+
+// Each calls fn for every bucket of the composite aggregation until all
+// buckets are consumed, fn returns an error or ctx is done.
+func (this *CompositeIterator) Each(ctx context.Context, fn func(bucket *CompositeBucket) error) error {
+	for {
+		buckets, err := this.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, bucket := range buckets {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(bucket); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Stream runs the iteration in the background, sending all buckets to the
+// returned buckets channel. Once the iteration is done the buckets channel is
+// closed and the error, if any, is sent on the error channel. The buckets
+// channel must be drained, or ctx cancelled, for the iteration to finish.
+func (this *CompositeIterator) Stream(ctx context.Context) (<-chan *CompositeBucket, <-chan error) {
+	var (
+		buckets = make(chan *CompositeBucket)
+		errc    = make(chan error, 1)
+	)
+	go func() {
+		err := this.Each(ctx, func(bucket *CompositeBucket) error {
+			select {
+			case buckets <- bucket:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		close(buckets)
+		errc <- err
+		close(errc)
+	}()
+	return buckets, errc
+}
+
+// EachRow is like Each, but flattens every bucket into a table row as done
+// by FlattenAggregations. The columns are passed along with every row.
+func (this *CompositeIterator) EachRow(ctx context.Context, docCount bool, fn func(columns []string, row []interface{}) error) error {
+	for {
+		raw, err := this.next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(map[string]interface{}{
+			this.name: map[string]interface{}{"buckets": raw},
+		})
+		if err != nil {
+			return err
+		}
+		table, err := FlattenAggregations(data, docCount)
+		if err != nil {
+			return err
+		}
+		for _, row := range table.Rows {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(table.Columns, row); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (this *CompositeIterator) next(ctx context.Context) ([]json.RawMessage, error) {
+	if this.done {
+		return nil, io.EOF
+	}
+	query, err := this.pageQuery()
+	if err != nil {
+		return nil, err
+	}
+	result, err := query.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if result.Aggregations == nil {
+		return nil, fmt.Errorf("composite aggregation %q missing from search result", this.name)
+	}
+	aggs := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(*result.Aggregations, &aggs); err != nil {
+		return nil, err
+	}
+	data, ok := aggs[this.name]
+	if !ok {
+		return nil, fmt.Errorf("composite aggregation %q missing from search result", this.name)
+	}
+	page := struct {
+		AfterKey map[string]interface{} `json:"after_key"`
+		Buckets  []json.RawMessage      `json:"buckets"`
+	}{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&page); err != nil {
+		return nil, err
+	}
+	if len(page.Buckets) == 0 {
+		this.done = true
+		return nil, io.EOF
+	}
+	if page.AfterKey == nil {
+		this.done = true
+	}
+	this.after = page.AfterKey
+	return page.Buckets, nil
+}
+
+// pageQuery returns a copy of the query without hits and with the "after"
+// option of the composite aggregation set, leaving the query and the options
+// originally passed to it untouched.
+func (this *CompositeIterator) pageQuery() (*Query, error) {
+	options, ok := this.query.aggregations[this.name].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("aggregation %q not found", this.name)
+	}
+	composite, ok := options["composite"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("aggregation %q is not a composite aggregation", this.name)
+	}
+	opts := copyRow(options)
+	comp := copyRow(composite)
+	if this.after != nil {
+		comp["after"] = this.after
+	} else {
+		delete(comp, "after")
+	}
+	opts["composite"] = comp
+	query := this.query.clone()
+	query.aggregations = copyRow(this.query.aggregations)
+	query.aggregations[this.name] = opts
+	query.limit = 0
+	return query, nil
+}
+
+func decodeCompositeBucket(data json.RawMessage) (*CompositeBucket, error) {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	bucket := &CompositeBucket{
+		Aggregations: make(map[string]json.RawMessage),
+	}
+	for name, value := range fields {
+		var err error
+		switch name {
+		case "key":
+			decoder := json.NewDecoder(bytes.NewReader(value))
+			decoder.UseNumber()
+			err = decoder.Decode(&bucket.Key)
+		case "doc_count":
+			err = json.Unmarshal(value, &bucket.DocCount)
+		default:
+			bucket.Aggregations[name] = value
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return bucket, nil
+}
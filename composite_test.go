@@ -0,0 +1,90 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func newCompositeTestClient(t *testing.T, afters *[]interface{}) *Client {
+	pages := []string{
+		`{"after_key": {"customer": "acme"}, "buckets": [{"key": {"customer": "acme"}, "doc_count": 3}]}`,
+		`{"after_key": {"customer": "globex"}, "buckets": [{"key": {"customer": "globex"}, "doc_count": 1}]}`,
+		`{"buckets": [{"key": {"customer": "initech"}, "doc_count": 2, "carriage": {"value": 7}}]}`,
+	}
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Size         *int `json:"size"`
+			Aggregations map[string]struct {
+				Composite map[string]interface{} `json:"composite"`
+			} `json:"aggregations"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		if body.Size == nil || *body.Size != 0 {
+			t.Errorf("expected size 0, got %v", body.Size)
+		}
+		*afters = append(*afters, body.Aggregations["by_customer"].Composite["after"])
+		if len(*afters) > len(pages) {
+			t.Errorf("unexpected request %d", len(*afters))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"hits": {"hits": []}, "aggregations": {"by_customer": ` + pages[len(*afters)-1] + `}}`))
+	})
+}
+
+func newCompositeTestQuery(client *Client) *Query {
+	return client.Search("md_fin_waybill").AddAgg("by_customer", "composite", map[string]interface{}{
+		"size":    1,
+		"sources": []interface{}{map[string]interface{}{"customer": map[string]interface{}{"terms": map[string]string{"field": "F_O_CustomerName.keyword"}}}},
+	})
+}
+
+func TestCompositeIteratorEach(t *testing.T) {
+	afters := make([]interface{}, 0)
+	query := newCompositeTestQuery(newCompositeTestClient(t, &afters))
+
+	keys := make([]interface{}, 0)
+	err := NewCompositeIterator(query, "by_customer").Each(context.Background(), func(bucket *CompositeBucket) error {
+		keys = append(keys, bucket.Key["customer"])
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []interface{}{"acme", "globex", "initech"}; !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected keys %v, got %v", expected, keys)
+	}
+	expected := []interface{}{nil, map[string]interface{}{"customer": "acme"}, map[string]interface{}{"customer": "globex"}}
+	if !reflect.DeepEqual(afters, expected) {
+		t.Errorf("expected after keys %v, got %v", expected, afters)
+	}
+	composite := query.aggregations["by_customer"].(map[string]interface{})["composite"].(map[string]interface{})
+	if _, ok := composite["after"]; ok || query.limit != 10 {
+		t.Errorf("the query was modified: %v, limit %d", composite, query.limit)
+	}
+}
+
+func TestCompositeIteratorStream(t *testing.T) {
+	afters := make([]interface{}, 0)
+	query := newCompositeTestQuery(newCompositeTestClient(t, &afters))
+
+	buckets, errc := NewCompositeIterator(query, "by_customer").Stream(context.Background())
+	count := 0
+	for bucket := range buckets {
+		count++
+		if bucket.Key["customer"] == "initech" && len(bucket.Aggregations["carriage"]) == 0 {
+			t.Errorf("expected the carriage sub-aggregation, got %v", bucket.Aggregations)
+		}
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 || len(afters) != 3 {
+		t.Errorf("expected 3 buckets in 3 requests, got %d in %d", count, len(afters))
+	}
+}
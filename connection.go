@@ -3,6 +3,7 @@ package go_elasticsearch
 import (
 	"context"
 	"net/http"
+	"net/http/httputil"
 	"net/url"
 	"time"
 )
@@ -17,6 +18,9 @@ type Client struct {
 	basicAuthPassword string // password for HTTP Basic Auth
 	DefaultProtocol   string
 	ConnectionTimeout time.Duration
+	errorlog          Logger // error log for critical messages
+	infolog           Logger // information log for e.g. response times
+	tracelog          Logger // trace log for debugging, e.g. request and response bodies
 }
 
 func NewClient(options ...OptionFunc) (*Client, error) {
@@ -51,6 +55,34 @@ func SetBasicAuth(username, password string) OptionFunc {
 	}
 }
 
+// SetErrorLog sets the logger for critical messages like failed requests.
+// It is nil by default, i.e. nothing is logged.
+func SetErrorLog(logger Logger) OptionFunc {
+	return func(client *Client) error {
+		client.errorlog = logger
+		return nil
+	}
+}
+
+// SetInfoLog sets the logger for informational messages, e.g. the method,
+// URL, status and duration of every request. It is nil by default.
+func SetInfoLog(logger Logger) OptionFunc {
+	return func(client *Client) error {
+		client.infolog = logger
+		return nil
+	}
+}
+
+// SetTraceLog sets the logger for dumping full HTTP requests and responses,
+// including their bodies. It is nil by default; be careful enabling it in
+// production as documents end up in the log.
+func SetTraceLog(logger Logger) OptionFunc {
+	return func(client *Client) error {
+		client.tracelog = logger
+		return nil
+	}
+}
+
 // PerformRequestOptions must be passed into PerformRequest.
 type PerformRequestOptions struct {
 	Method      string
@@ -84,21 +116,59 @@ func (this *Client) httpRequest(ctx context.Context, method, path string, params
 		}
 	}
 
+	this.dumpRequest((*http.Request)(request))
+
+	start := time.Now()
 	res, err := this.c.Do((*http.Request)(request).WithContext(ctx))
 	if err != nil {
+		this.errorf("elastic: %s %s failed: %v", method, path, err)
 		return nil, err
 	}
 
 	if res.Body != nil {
 		defer res.Body.Close()
 	}
+	this.dumpResponse(res)
+	this.infof("%s %s [status:%d, request:%.3fs]", method, pathWithParams, res.StatusCode, time.Since(start).Seconds())
+
 	if err := checkResponse((*http.Request)(request), res); err != nil {
-		response, err := this.newResponse(res)
+		this.errorf("elastic: %s %s failed: %v", method, path, err)
+		response, _ := this.newResponse(res)
 		return response, err
 	}
 	return this.newResponse(res)
 }
 
+func (this *Client) errorf(format string, args ...interface{}) {
+	if this.errorlog != nil {
+		this.errorlog.Printf(format, args...)
+	}
+}
+
+func (this *Client) infof(format string, args ...interface{}) {
+	if this.infolog != nil {
+		this.infolog.Printf(format, args...)
+	}
+}
+
+func (this *Client) dumpRequest(r *http.Request) {
+	if this.tracelog != nil {
+		out, err := httputil.DumpRequestOut(r, true)
+		if err == nil {
+			this.tracelog.Printf("%s\n", string(out))
+		}
+	}
+}
+
+func (this *Client) dumpResponse(r *http.Response) {
+	if this.tracelog != nil {
+		out, err := httputil.DumpResponse(r, true)
+		if err == nil {
+			this.tracelog.Printf("%s\n", string(out))
+		}
+	}
+}
+
 func (this *Client) Search(indexs ...string) *Query {
 	return NewQuery(this).Index(indexs...)
 }
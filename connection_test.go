@@ -0,0 +1,49 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := NewClient(SetUrl(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestQueryDo(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/md_fin_waybill/_search" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"took": 1, "hits": {"total": {"value": 1, "relation": "eq"}, "hits": [{"_id": "1", "_index": "md_fin_waybill", "_score": 1}]}}`))
+	})
+	result, err := client.Search("md_fin_waybill").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TotalHits() != 1 || len(result.Hits.Hits) != 1 || result.Hits.Hits[0].ID != "1" {
+		t.Errorf("unexpected result %s", string(result.RawBody))
+	}
+}
+
+func TestQueryDoError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"type": "index_not_found_exception", "reason": "no such index [md_fin_waybill]"}, "status": 404}`))
+	})
+	_, err := client.Search("md_fin_waybill").Do(context.Background())
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected *Error, got %T: %v", err, err)
+	}
+	if e.Status != http.StatusNotFound || e.Details == nil || e.Details.Type != "index_not_found_exception" {
+		t.Errorf("unexpected error %v", e)
+	}
+}
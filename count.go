@@ -0,0 +1,54 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// countOptions are the query options that _count supports. The others, e.g.
+// "search_type", only apply to searches and are not sent.
+var countOptions = map[string]bool{
+	"preference": true, "routing": true, "terminate_after": true, "min_score": true,
+	"allow_no_indices": true, "expand_wildcards": true, "ignore_unavailable": true,
+	"ignore_throttled": true, "analyzer": true, "analyze_wildcard": true,
+	"default_operator": true, "df": true, "lenient": true,
+}
+
+// Count returns the number of documents matching the where conditions of the
+// query, using the _count endpoint instead of running a search. The count is
+// done on the indices of the query, also if it has a point in time.
+func (this *Query) Count(ctx context.Context) (int64, error) {
+	builder := QueryBuilder{}
+	path, params, err := this.buildIndexUrl("_count")
+	if err != nil {
+		return 0, err
+	}
+	for name := range params {
+		if !countOptions[name] {
+			params.Del(name)
+		}
+	}
+	query, err := builder.BuildQuery(this)
+	if err != nil {
+		return 0, err
+	}
+	var body interface{}
+	if query != nil {
+		body = map[string]interface{}{"query": query}
+	}
+	response, err := this.client.httpRequest(ctx, "POST", path, params, body, false)
+	if err != nil {
+		return 0, err
+	}
+	ret := new(CountResult)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return 0, err
+	}
+	return ret.Count, nil
+}
+
+// CountResult is the response of the _count endpoint.
+type CountResult struct {
+	Count  int64      `json:"count"`
+	Shards ShardsInfo `json:"_shards"`
+}
@@ -0,0 +1,66 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestQueryCount(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/md_fin_waybill/_count" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		expected := `{"query":{"bool":{"must":[{"range":{"F_FJScan_Flag":{"lt":"1"}}}]}}}`
+		if string(body) != expected {
+			t.Errorf("expected body %s, got %s", expected, string(body))
+		}
+		w.Write([]byte(`{"count": 42, "_shards": {"total": 1, "successful": 1, "failed": 0}}`))
+	})
+	count, err := client.Search("md_fin_waybill").AndWhere("<", "F_FJScan_Flag", "1").Count(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 42 {
+		t.Errorf("expected 42, got %d", count)
+	}
+}
+
+func TestQueryBuildLimitZero(t *testing.T) {
+	client, _ := NewClient()
+	builder := QueryBuilder{}
+	body, err := builder.Build(client.Search("md_fin_waybill").Limit(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size, ok := body["size"]; !ok || size != 0 {
+		t.Errorf("expected size 0, got %v", size)
+	}
+}
+
+func TestQueryCountPointInTime(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/md_fin_waybill/_count" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.RawQuery != "preference=_local" {
+			t.Errorf("expected only preference, got %q", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"count": 42}`))
+	})
+	query := client.Search("md_fin_waybill").PointInTime("pit-1", "1m").
+		Options("preference", "_local").Options("search_type", "dfs_query_then_fetch")
+	count, err := query.Count(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 42 {
+		t.Errorf("expected 42, got %d", count)
+	}
+
+	if _, err := client.Search().PointInTime("pit-1", "1m").Count(context.Background()); err == nil {
+		t.Error("expected error counting a point in time without indices")
+	}
+}
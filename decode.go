@@ -0,0 +1,139 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Decode unmarshals the _source of every hit of the search result into a T.
+// T may be a struct or a pointer to a struct. Fields tagged with `es:"_id"`,
+// `es:"_index"`, `es:"_score"`, `es:"_version"` or `es:"sort"` are populated
+// from the metadata of the hit:
+//
+//	type Waybill struct {
+//		ID      string  `json:"-" es:"_id"`
+//		Version int64   `json:"-" es:"_version"`
+//		Freight float64 `json:"F_Freight"`
+//	}
+//	waybills, err := Decode[Waybill](result)
+func Decode[T any](result *SearchResult) ([]T, error) {
+	ret := make([]T, 0)
+	if err := DecodeHits(result, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// DoInto executes the query and decodes the hits into dst, which must be a
+// pointer to a slice. See Decode for the supported struct tags.
+func (this *Query) DoInto(ctx context.Context, dst interface{}) error {
+	result, err := this.Do(ctx)
+	if err != nil {
+		return err
+	}
+	return DecodeHits(result, dst)
+}
+
+// DecodeHits is the non-generic form of Decode: it decodes the hits of the
+// search result into dst, which must be a pointer to a slice.
+func DecodeHits(result *SearchResult, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("decode destination must be a pointer to a slice, got %T", dst)
+	}
+	if result == nil {
+		return fmt.Errorf("decode: no search result")
+	}
+	slice := rv.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	out := reflect.MakeSlice(slice.Type(), 0, len(result.Hits.Hits))
+	for i, hit := range result.Hits.Hits {
+		if hit == nil {
+			return fmt.Errorf("decode hit %d: missing hit", i)
+		}
+		item := reflect.New(elemType)
+		if hit.Source != nil {
+			if err := json.Unmarshal(*hit.Source, item.Interface()); err != nil {
+				return fmt.Errorf("decode hit %s: %v", hit.ID, err)
+			}
+		}
+		if elemType.Kind() == reflect.Struct {
+			if err := setHitMetadata(item.Elem(), hit); err != nil {
+				return fmt.Errorf("decode hit %s: %v", hit.ID, err)
+			}
+		}
+		if isPtr {
+			out = reflect.Append(out, item)
+		} else {
+			out = reflect.Append(out, item.Elem())
+		}
+	}
+	slice.Set(out)
+	return nil
+}
+
+func setHitMetadata(v reflect.Value, hit *SearchHit) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := setHitMetadata(v.Field(i), hit); err != nil {
+				return err
+			}
+			continue
+		}
+		var value interface{}
+		switch field.Tag.Get("es") {
+		case "_id":
+			value = hit.ID
+		case "_index":
+			value = hit.Index
+		case "_score":
+			value = hit.Score
+		case "_version":
+			value = hit.Version
+		case "sort":
+			value = hit.Sort
+		default:
+			continue
+		}
+		if err := setField(v.Field(i), value); err != nil {
+			return fmt.Errorf("field %s: %v", field.Name, err)
+		}
+	}
+	return nil
+}
+
+// setField assigns value to field, converting between compatible types and
+// allocating pointer fields as needed. Nil values leave the field untouched.
+func setField(field reflect.Value, value interface{}) error {
+	if !field.CanSet() || value == nil {
+		return nil
+	}
+	val := reflect.ValueOf(value)
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	target := field
+	if field.Kind() == reflect.Ptr {
+		target = reflect.New(field.Type().Elem()).Elem()
+	}
+	if (target.Kind() == reflect.String) != (val.Kind() == reflect.String) || !val.Type().ConvertibleTo(target.Type()) {
+		return fmt.Errorf("cannot assign %s to %s", val.Type(), field.Type())
+	}
+	target.Set(val.Convert(target.Type()))
+	if field.Kind() == reflect.Ptr {
+		field.Set(target.Addr())
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package go_elasticsearch
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type testWaybill struct {
+	ID       string        `json:"-" es:"_id"`
+	Index    string        `json:"-" es:"_index"`
+	Version  *int64        `json:"-" es:"_version"`
+	Sort     []interface{} `json:"-" es:"sort"`
+	Customer string        `json:"F_O_CustomerName"`
+	Freight  float64       `json:"F_Freight"`
+}
+
+func TestDecode(t *testing.T) {
+	result := new(SearchResult)
+	err := json.Unmarshal([]byte(`{"hits": {"hits": [
+		{"_id": "1", "_index": "md_fin_waybill", "_version": 3, "sort": [1583539200000], "_source": {"F_O_CustomerName": "acme", "F_Freight": 20.5}},
+		{"_id": "2", "_index": "md_fin_waybill", "_source": {"F_O_CustomerName": "globex", "F_Freight": 3}}
+	]}}`), result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waybills, err := Decode[testWaybill](result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(waybills) != 2 {
+		t.Fatalf("expected 2 waybills, got %d", len(waybills))
+	}
+	first := waybills[0]
+	if first.ID != "1" || first.Index != "md_fin_waybill" || first.Customer != "acme" || first.Freight != 20.5 {
+		t.Errorf("unexpected waybill %+v", first)
+	}
+	if first.Version == nil || *first.Version != 3 {
+		t.Errorf("expected version 3, got %v", first.Version)
+	}
+	if len(first.Sort) != 1 {
+		t.Errorf("expected sort values, got %v", first.Sort)
+	}
+	if waybills[1].Version != nil {
+		t.Errorf("expected no version, got %v", *waybills[1].Version)
+	}
+
+	pointers, err := Decode[*testWaybill](result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pointers[1].ID != "2" || pointers[1].Customer != "globex" {
+		t.Errorf("unexpected waybill %+v", pointers[1])
+	}
+
+	if err := DecodeHits(result, []testWaybill{}); err == nil {
+		t.Error("expected error decoding into a non-pointer")
+	}
+	if _, err := Decode[testWaybill](nil); err == nil {
+		t.Error("expected error decoding a nil result")
+	}
+	result.Hits.Hits = append(result.Hits.Hits, nil)
+	if _, err := Decode[testWaybill](result); err == nil {
+		t.Error("expected error decoding a nil hit")
+	}
+}
@@ -0,0 +1,144 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// DeleteService deletes a document by id. A missing document is returned as
+// *Error with status 404, see IsNotFound.
+//
+//	response, err := client.Delete("md_fin_waybill").Id("W123").Do(ctx)
+type DeleteService struct {
+	client        *Client
+	index         string
+	typ           string
+	id            string
+	routing       string
+	refresh       string
+	timeout       string
+	version       *int64
+	versionType   string
+	ifSeqNo       *int64
+	ifPrimaryTerm *int64
+}
+
+func NewDeleteService(c *Client) *DeleteService {
+	return &DeleteService{
+		client: c,
+	}
+}
+
+func (this *Client) Delete(index string) *DeleteService {
+	return NewDeleteService(this).Index(index)
+}
+
+func (this *DeleteService) Index(index string) *DeleteService {
+	this.index = index
+	return this
+}
+
+// Type sets the type of the document, for Elasticsearch 6.x.
+func (this *DeleteService) Type(typ string) *DeleteService {
+	this.typ = typ
+	return this
+}
+
+func (this *DeleteService) Id(id string) *DeleteService {
+	this.id = id
+	return this
+}
+
+func (this *DeleteService) Routing(routing string) *DeleteService {
+	this.routing = routing
+	return this
+}
+
+// Refresh sets when the change is made visible to search: "true",
+// "wait_for" or "false".
+func (this *DeleteService) Refresh(refresh string) *DeleteService {
+	this.refresh = refresh
+	return this
+}
+
+func (this *DeleteService) Timeout(timeout string) *DeleteService {
+	this.timeout = timeout
+	return this
+}
+
+func (this *DeleteService) Version(version int64) *DeleteService {
+	this.version = &version
+	return this
+}
+
+func (this *DeleteService) VersionType(versionType string) *DeleteService {
+	this.versionType = versionType
+	return this
+}
+
+// IfSeqNo only deletes the document if its sequence number is seqNo, see
+// IndexService.IfSeqNo.
+func (this *DeleteService) IfSeqNo(seqNo int64) *DeleteService {
+	this.ifSeqNo = &seqNo
+	return this
+}
+
+func (this *DeleteService) IfPrimaryTerm(primaryTerm int64) *DeleteService {
+	this.ifPrimaryTerm = &primaryTerm
+	return this
+}
+
+func (this *DeleteService) buildUrl() (string, url.Values, error) {
+	path, err := documentPath(this.index, this.typ, this.id)
+	if err != nil {
+		return "", url.Values{}, err
+	}
+	params := url.Values{}
+	if this.routing != "" {
+		params.Set("routing", this.routing)
+	}
+	if this.refresh != "" {
+		params.Set("refresh", this.refresh)
+	}
+	if this.timeout != "" {
+		params.Set("timeout", this.timeout)
+	}
+	if this.version != nil {
+		params.Set("version", strconv.FormatInt(*this.version, 10))
+	}
+	if this.versionType != "" {
+		params.Set("version_type", this.versionType)
+	}
+	if this.ifSeqNo != nil {
+		params.Set("if_seq_no", strconv.FormatInt(*this.ifSeqNo, 10))
+	}
+	if this.ifPrimaryTerm != nil {
+		params.Set("if_primary_term", strconv.FormatInt(*this.ifPrimaryTerm, 10))
+	}
+	return path, params, nil
+}
+
+func (this *DeleteService) Do(ctx context.Context) (*IndexResponse, error) {
+	if this.index == "" {
+		return nil, fmt.Errorf("index is required")
+	}
+	if this.id == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+	path, params, err := this.buildUrl()
+	if err != nil {
+		return nil, err
+	}
+	response, err := this.client.httpRequest(ctx, "DELETE", path, params, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(IndexResponse)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,193 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/wh5231/go-elasticsearch/uritemplates"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DeleteByQueryService deletes the documents matching a query with the
+// _delete_by_query endpoint.
+//
+//	response, err := client.DeleteByQuery("md_fin_waybill").
+//		Query(client.Search().AndWhere("<", "F_OrderTime", "2018-01-01T00:00:00")).
+//		WaitForCompletion(false).
+//		Do(ctx)
+type DeleteByQueryService struct {
+	client            *Client
+	index             []string
+	query             *Query
+	conflicts         string
+	maxDocs           int64
+	slices            interface{}
+	scrollSize        int
+	routing           string
+	refresh           bool
+	requestsPerSecond *float64
+	waitForCompletion *bool
+	timeout           string
+}
+
+func NewDeleteByQueryService(c *Client) *DeleteByQueryService {
+	return &DeleteByQueryService{
+		client: c,
+		index:  make([]string, 0),
+	}
+}
+
+func (this *Client) DeleteByQuery(index ...string) *DeleteByQueryService {
+	return NewDeleteByQueryService(this).Index(index...)
+}
+
+func (this *DeleteByQueryService) Index(index ...string) *DeleteByQueryService {
+	this.index = append(this.index, index...)
+	return this
+}
+
+// Query sets the documents to delete: those matching the where conditions
+// of query. Without index, the indices of query are used.
+func (this *DeleteByQueryService) Query(query *Query) *DeleteByQueryService {
+	this.query = query
+	return this
+}
+
+// Conflicts set to "proceed" counts version conflicts instead of aborting.
+func (this *DeleteByQueryService) Conflicts(conflicts string) *DeleteByQueryService {
+	this.conflicts = conflicts
+	return this
+}
+
+func (this *DeleteByQueryService) MaxDocs(maxDocs int64) *DeleteByQueryService {
+	this.maxDocs = maxDocs
+	return this
+}
+
+// Slices splits the delete into parallel slices: a number or "auto".
+func (this *DeleteByQueryService) Slices(slices interface{}) *DeleteByQueryService {
+	this.slices = slices
+	return this
+}
+
+// ScrollSize sets the number of documents deleted per batch.
+func (this *DeleteByQueryService) ScrollSize(scrollSize int) *DeleteByQueryService {
+	this.scrollSize = scrollSize
+	return this
+}
+
+func (this *DeleteByQueryService) Routing(routing string) *DeleteByQueryService {
+	this.routing = routing
+	return this
+}
+
+func (this *DeleteByQueryService) Refresh(refresh bool) *DeleteByQueryService {
+	this.refresh = refresh
+	return this
+}
+
+// RequestsPerSecond throttles the delete; -1 disables throttling.
+func (this *DeleteByQueryService) RequestsPerSecond(requestsPerSecond float64) *DeleteByQueryService {
+	this.requestsPerSecond = &requestsPerSecond
+	return this
+}
+
+// WaitForCompletion set to false runs the delete as a task and returns its
+// id in BulkIndexByScrollResponse.Task.
+func (this *DeleteByQueryService) WaitForCompletion(waitForCompletion bool) *DeleteByQueryService {
+	this.waitForCompletion = &waitForCompletion
+	return this
+}
+
+func (this *DeleteByQueryService) Timeout(timeout string) *DeleteByQueryService {
+	this.timeout = timeout
+	return this
+}
+
+// Body returns the body of the request. A query is required; to delete all
+// documents, set a match_all query explicitly:
+//
+//	client.DeleteByQuery("md_fin_waybill").Query(client.Search().Query(map[string]interface{}{"match_all": map[string]interface{}{}}))
+func (this *DeleteByQueryService) Body() (map[string]interface{}, error) {
+	var query interface{}
+	if this.query != nil {
+		builder := QueryBuilder{}
+		built, err := builder.BuildQuery(this.query)
+		if err != nil {
+			return nil, err
+		}
+		query = built
+	}
+	if query == nil {
+		return nil, fmt.Errorf("delete by query requires a query")
+	}
+	body := map[string]interface{}{"query": query}
+	if this.maxDocs > 0 {
+		body["max_docs"] = this.maxDocs
+	}
+	return body, nil
+}
+
+func (this *DeleteByQueryService) buildUrl() (string, url.Values, error) {
+	index := this.index
+	if len(index) == 0 && this.query != nil {
+		index = this.query.index
+	}
+	if len(index) == 0 {
+		return "", url.Values{}, fmt.Errorf("delete by query requires an index")
+	}
+	path, err := uritemplates.Expand("/{index}/_delete_by_query", map[string]string{
+		"index": strings.Join(index, ","),
+	})
+	if err != nil {
+		return "", url.Values{}, err
+	}
+	params := url.Values{}
+	if this.conflicts != "" {
+		params.Set("conflicts", this.conflicts)
+	}
+	if this.slices != nil {
+		params.Set("slices", fmt.Sprint(this.slices))
+	}
+	if this.scrollSize > 0 {
+		params.Set("scroll_size", strconv.Itoa(this.scrollSize))
+	}
+	if this.routing != "" {
+		params.Set("routing", this.routing)
+	}
+	if this.refresh {
+		params.Set("refresh", "true")
+	}
+	if this.requestsPerSecond != nil {
+		params.Set("requests_per_second", strconv.FormatFloat(*this.requestsPerSecond, 'f', -1, 64))
+	}
+	if this.waitForCompletion != nil {
+		params.Set("wait_for_completion", strconv.FormatBool(*this.waitForCompletion))
+	}
+	if this.timeout != "" {
+		params.Set("timeout", this.timeout)
+	}
+	return path, params, nil
+}
+
+func (this *DeleteByQueryService) Do(ctx context.Context) (*BulkIndexByScrollResponse, error) {
+	path, params, err := this.buildUrl()
+	if err != nil {
+		return nil, err
+	}
+	body, err := this.Body()
+	if err != nil {
+		return nil, err
+	}
+	response, err := this.client.httpRequest(ctx, "POST", path, params, body, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(BulkIndexByScrollResponse)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,77 @@
+package go_elasticsearch
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/wh5231/go-elasticsearch/uritemplates"
+)
+
+// ErrSourceNotFetched is returned when decoding a document that exists but
+// whose source was not fetched, e.g. with FetchSource(false).
+var ErrSourceNotFetched = errors.New("document source was not fetched")
+
+// documentPath builds the path of a document, "/{index}/_doc/{id}", or
+// "/{index}/{type}/{id}" if a type is set for Elasticsearch 6.x. Without an
+// id the path of the index endpoint is returned, letting Elasticsearch
+// generate the id.
+func documentPath(index, typ, id string) (string, error) {
+	if typ == "" {
+		typ = "_doc"
+	}
+	if id == "" {
+		return uritemplates.Expand("/{index}/{type}", map[string]string{
+			"index": index,
+			"type":  typ,
+		})
+	}
+	return uritemplates.Expand("/{index}/{type}/{id}", map[string]string{
+		"index": index,
+		"type":  typ,
+		"id":    id,
+	})
+}
+
+// IndexResponse is the response of a write of a single document, e.g. by
+// Index, Update or Delete. Result is "created", "updated", "deleted",
+// "noop" or "not_found".
+type IndexResponse struct {
+	Index         string      `json:"_index"`
+	Type          string      `json:"_type,omitempty"` // removed in Elasticsearch 8
+	Id            string      `json:"_id"`
+	Version       int64       `json:"_version,omitempty"`
+	Result        string      `json:"result,omitempty"`
+	Shards        *ShardsInfo `json:"_shards,omitempty"`
+	SeqNo         int64       `json:"_seq_no,omitempty"`
+	PrimaryTerm   int64       `json:"_primary_term,omitempty"`
+	Status        int         `json:"status,omitempty"`
+	ForcedRefresh bool        `json:"forced_refresh,omitempty"`
+}
+
+// GetResult is a document returned by Get or MultiGet. Found is false if
+// the document does not exist; Error is only set by MultiGet.
+type GetResult struct {
+	Index       string                   `json:"_index"`
+	Type        string                   `json:"_type,omitempty"` // removed in Elasticsearch 8
+	Id          string                   `json:"_id"`
+	Version     *int64                   `json:"_version,omitempty"`
+	SeqNo       *int64                   `json:"_seq_no,omitempty"`
+	PrimaryTerm *int64                   `json:"_primary_term,omitempty"`
+	Routing     string                   `json:"_routing,omitempty"`
+	Found       bool                     `json:"found"`
+	Source      *json.RawMessage         `json:"_source,omitempty"`
+	Fields      map[string][]interface{} `json:"fields,omitempty"`
+	Error       *ErrorDetails            `json:"error,omitempty"`
+}
+
+// Decode decodes the source of the document into dst. It returns an *Error
+// with status 404 if the document was not found, and ErrSourceNotFetched if
+// its source was excluded.
+func (this *GetResult) Decode(dst interface{}) error {
+	if !this.Found {
+		return &Error{Status: 404}
+	}
+	if this.Source == nil {
+		return ErrSourceNotFetched
+	}
+	return json.Unmarshal(*this.Source, dst)
+}
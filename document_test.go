@@ -0,0 +1,161 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestIndexDocument(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/md_fin_waybill/_doc/W123" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.RawQuery != "if_primary_term=1&if_seq_no=7&refresh=wait_for&routing=c1" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != `{"F_FJScan_Flag":0}` {
+			t.Errorf("unexpected body %s", string(body))
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"_index": "md_fin_waybill", "_id": "W123", "_version": 2, "result": "updated", "_seq_no": 8, "_primary_term": 1}`))
+	})
+	response, err := client.Index("md_fin_waybill").Id("W123").
+		BodyJson(map[string]interface{}{"F_FJScan_Flag": 0}).
+		Routing("c1").Refresh("wait_for").IfSeqNo(7).IfPrimaryTerm(1).
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Result != "updated" || response.SeqNo != 8 {
+		t.Errorf("unexpected response %+v", response)
+	}
+}
+
+func TestIndexDocumentConflict(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"error": {"type": "version_conflict_engine_exception", "reason": "[W123]: version conflict"}, "status": 409}`))
+	})
+	_, err := client.Index("md_fin_waybill").Type("md_fin_waybill").Id("W123").BodyString(`{}`).IfSeqNo(7).IfPrimaryTerm(1).Do(context.Background())
+	if !IsConflict(err) {
+		t.Errorf("expected a conflict, got %v", err)
+	}
+}
+
+func TestGetDocument(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/md_fin_waybill/_doc/W123":
+			if r.URL.Query().Get("_source_includes") != "F_OrderNo,F_FJScan_Flag" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"_index": "md_fin_waybill", "_id": "W123", "_version": 2, "found": true, "_source": {"F_OrderNo": "O1", "F_FJScan_Flag": 1}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"_index": "md_fin_waybill", "_id": "W404", "found": false}`))
+		}
+	})
+	doc, err := client.Get("md_fin_waybill").Id("W123").SourceIncludes("F_OrderNo", "F_FJScan_Flag").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var waybill struct {
+		OrderNo string `json:"F_OrderNo"`
+		Flag    int    `json:"F_FJScan_Flag"`
+	}
+	if err := doc.Decode(&waybill); err != nil {
+		t.Fatal(err)
+	}
+	if waybill.OrderNo != "O1" || waybill.Flag != 1 {
+		t.Errorf("unexpected document %+v", waybill)
+	}
+
+	if _, err := client.Get("md_fin_waybill").Id("W404").Do(context.Background()); !IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestExistsDocument(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "HEAD" {
+			t.Errorf("unexpected method %s", r.Method)
+		}
+		if r.URL.Path != "/md_fin_waybill/_doc/W123" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	for id, expected := range map[string]bool{"W123": true, "W404": false} {
+		exists, err := client.Exists("md_fin_waybill").Id(id).Do(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exists != expected {
+			t.Errorf("%s: expected exists %v, got %v", id, expected, exists)
+		}
+	}
+}
+
+func TestUpdateDocument(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/md_fin_waybill/_update/W123" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("retry_on_conflict") != "3" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		expected := `{"_source":true,"script":{"params":{"n":1},"source":"ctx._source.F_Count += params.n"},"upsert":{"F_Count":1}}`
+		if string(body) != expected {
+			t.Errorf("expected body %s, got %s", expected, string(body))
+		}
+		w.Write([]byte(`{"_index": "md_fin_waybill", "_id": "W123", "_version": 3, "result": "updated",
+			"get": {"found": true, "_source": {"F_Count": 2}}}`))
+	})
+	response, err := client.Update("md_fin_waybill").Id("W123").
+		Script(map[string]interface{}{"source": "ctx._source.F_Count += params.n", "params": map[string]interface{}{"n": 1}}).
+		Upsert(map[string]interface{}{"F_Count": 1}).
+		RetryOnConflict(3).
+		FetchSource(true).
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Version != 3 || response.GetResult == nil || !response.GetResult.Found {
+		t.Errorf("unexpected response %+v", response)
+	}
+
+	if _, err := client.Update("md_fin_waybill").Id("W123").Do(context.Background()); err == nil {
+		t.Error("expected error for an update without doc or script")
+	}
+}
+
+func TestDeleteDocument(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" || r.URL.Path != "/md_fin_waybill/md_fin_waybill/W123" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"_index": "md_fin_waybill", "_type": "md_fin_waybill", "_id": "W123", "result": "deleted"}`))
+	})
+	response, err := client.Delete("md_fin_waybill").Type("md_fin_waybill").Id("W123").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Result != "deleted" {
+		t.Errorf("unexpected response %+v", response)
+	}
+}
+
+func TestGetResultDecode(t *testing.T) {
+	var dst map[string]interface{}
+	missing := &GetResult{Id: "W404", Found: false}
+	if err := missing.Decode(&dst); !IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	excluded := &GetResult{Id: "W123", Found: true}
+	if err := excluded.Decode(&dst); err != ErrSourceNotFetched || IsNotFound(err) {
+		t.Errorf("expected ErrSourceNotFetched, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package go_elasticsearch
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -16,13 +17,15 @@ func checkResponse(req *http.Request, res *http.Response) error {
 // createResponseError creates an Error structure from the HTTP response,
 // its status code and the error information sent by Elasticsearch.
 func createResponseError(r *http.Response) error {
-	if r.Body != nil {
+	if r.Body == nil {
 		return &Error{Status: r.StatusCode}
 	}
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		return &Error{Status: r.StatusCode}
 	}
+	// keep the body readable for the response returned along with the error
+	r.Body = ioutil.NopCloser(bytes.NewReader(data))
 	errReply := new(Error)
 	err = json.Unmarshal(data, &errReply)
 	if err != nil {
@@ -66,3 +69,20 @@ func (e *Error) Error() string {
 	}
 	return fmt.Sprintf("elastic: Error %d (%s)", e.Status, http.StatusText(e.Status))
 }
+
+// IsNotFound returns true if err is an *Error with status 404, e.g. for a
+// missing document or index.
+func IsNotFound(err error) bool {
+	return isStatus(err, http.StatusNotFound)
+}
+
+// IsConflict returns true if err is an *Error with status 409, e.g. for a
+// version conflict.
+func IsConflict(err error) bool {
+	return isStatus(err, http.StatusConflict)
+}
+
+func isStatus(err error, status int) bool {
+	e, ok := err.(*Error)
+	return ok && e != nil && e.Status == status
+}
@@ -0,0 +1,95 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// ExistsService checks whether a document exists, without fetching it.
+//
+//	exists, err := client.Exists("md_fin_waybill").Id("W123").Do(ctx)
+type ExistsService struct {
+	client     *Client
+	index      string
+	typ        string
+	id         string
+	routing    string
+	preference string
+	refresh    bool
+}
+
+func NewExistsService(c *Client) *ExistsService {
+	return &ExistsService{
+		client: c,
+	}
+}
+
+func (this *Client) Exists(index string) *ExistsService {
+	return NewExistsService(this).Index(index)
+}
+
+func (this *ExistsService) Index(index string) *ExistsService {
+	this.index = index
+	return this
+}
+
+// Type sets the type of the document, for Elasticsearch 6.x.
+func (this *ExistsService) Type(typ string) *ExistsService {
+	this.typ = typ
+	return this
+}
+
+func (this *ExistsService) Id(id string) *ExistsService {
+	this.id = id
+	return this
+}
+
+func (this *ExistsService) Routing(routing string) *ExistsService {
+	this.routing = routing
+	return this
+}
+
+func (this *ExistsService) Preference(preference string) *ExistsService {
+	this.preference = preference
+	return this
+}
+
+// Refresh refreshes the shard before checking the document.
+func (this *ExistsService) Refresh(refresh bool) *ExistsService {
+	this.refresh = refresh
+	return this
+}
+
+// Do returns whether the document exists. A missing document is not an
+// error.
+func (this *ExistsService) Do(ctx context.Context) (bool, error) {
+	if this.index == "" {
+		return false, fmt.Errorf("index is required")
+	}
+	if this.id == "" {
+		return false, fmt.Errorf("id is required")
+	}
+	path, err := documentPath(this.index, this.typ, this.id)
+	if err != nil {
+		return false, err
+	}
+	params := url.Values{}
+	if this.routing != "" {
+		params.Set("routing", this.routing)
+	}
+	if this.preference != "" {
+		params.Set("preference", this.preference)
+	}
+	if this.refresh {
+		params.Set("refresh", "true")
+	}
+	_, err = this.client.httpRequest(ctx, "HEAD", path, params, nil, false)
+	if IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
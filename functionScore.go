@@ -0,0 +1,375 @@
+package go_elasticsearch
+
+import "fmt"
+
+// FunctionScoreQuery modifies the score of the hits of a query with score
+// functions, e.g. to rank dispatch candidates by distance and freshness.
+// Use it with Query.FunctionScore to score the hits of the where conditions,
+// or as the query of a Rescore.
+//
+//	client.Search("md_dispatch").AndWhere("in", "F_Status", "open").FunctionScore(
+//		NewFunctionScoreQuery().
+//			AddScoreFunc(NewGaussDecayFunction("F_Location").Origin("31.23,121.47").Scale("10km")).
+//			AddScoreFunc(NewExpDecayFunction("F_UpdateTime").Origin("now").Scale("1h").Decay(0.5)).
+//			ScoreMode("multiply"),
+//	)
+type FunctionScoreQuery struct {
+	query     interface{}
+	filters   []interface{}
+	functions []ScoreFunction
+	scoreMode string
+	boostMode string
+	maxBoost  *float64
+	minScore  *float64
+	boost     *float64
+}
+
+func NewFunctionScoreQuery() *FunctionScoreQuery {
+	return &FunctionScoreQuery{
+		filters:   make([]interface{}, 0),
+		functions: make([]ScoreFunction, 0),
+	}
+}
+
+// Query sets the query whose hits are scored: a *Query whose where
+// conditions are used, a typed query, or a raw query. With Query.FunctionScore
+// it defaults to the where conditions of that query.
+func (this *FunctionScoreQuery) Query(query interface{}) *FunctionScoreQuery {
+	this.query = query
+	return this
+}
+
+// Add adds a score function applied to the hits matching filter only. The
+// filter is a *Query whose where conditions are used, or a raw query.
+func (this *FunctionScoreQuery) Add(filter interface{}, function ScoreFunction) *FunctionScoreQuery {
+	this.filters = append(this.filters, filter)
+	this.functions = append(this.functions, function)
+	return this
+}
+
+// AddScoreFunc adds a score function applied to all hits.
+func (this *FunctionScoreQuery) AddScoreFunc(function ScoreFunction) *FunctionScoreQuery {
+	return this.Add(nil, function)
+}
+
+// ScoreMode sets how the scores of the functions are combined: "multiply",
+// "sum", "avg", "first", "max" or "min".
+func (this *FunctionScoreQuery) ScoreMode(scoreMode string) *FunctionScoreQuery {
+	this.scoreMode = scoreMode
+	return this
+}
+
+// BoostMode sets how the combined score of the functions is combined with
+// the score of the query: "multiply", "replace", "sum", "avg", "max" or "min".
+func (this *FunctionScoreQuery) BoostMode(boostMode string) *FunctionScoreQuery {
+	this.boostMode = boostMode
+	return this
+}
+
+func (this *FunctionScoreQuery) MaxBoost(maxBoost float64) *FunctionScoreQuery {
+	this.maxBoost = &maxBoost
+	return this
+}
+
+// MinScore leaves out the hits scoring less than minScore.
+func (this *FunctionScoreQuery) MinScore(minScore float64) *FunctionScoreQuery {
+	this.minScore = &minScore
+	return this
+}
+
+func (this *FunctionScoreQuery) Boost(boost float64) *FunctionScoreQuery {
+	this.boost = &boost
+	return this
+}
+
+func (this *FunctionScoreQuery) Source() (interface{}, error) {
+	return this.source(nil)
+}
+
+// source returns the body of the query, scoring defaultQuery if no query was
+// set explicitly.
+func (this *FunctionScoreQuery) source(defaultQuery interface{}) (interface{}, error) {
+	options := make(map[string]interface{})
+	query := defaultQuery
+	if this.query != nil {
+		built, err := buildQueryValue(this.query)
+		if err != nil {
+			return nil, err
+		}
+		query = built
+	}
+	if query != nil {
+		options["query"] = query
+	}
+
+	functions := make([]interface{}, 0, len(this.functions))
+	for i, function := range this.functions {
+		fn := make(map[string]interface{})
+		if this.filters[i] != nil {
+			filter, err := buildQueryValue(this.filters[i])
+			if err != nil {
+				return nil, err
+			}
+			if filter != nil {
+				fn["filter"] = filter
+			}
+		}
+		if weight := function.GetWeight(); weight != nil {
+			fn["weight"] = *weight
+		}
+		if name := function.Name(); name != "weight" {
+			source, err := function.Source()
+			if err != nil {
+				return nil, fmt.Errorf("%s function: %v", name, err)
+			}
+			fn[name] = source
+		}
+		functions = append(functions, fn)
+	}
+	options["functions"] = functions
+
+	if this.scoreMode != "" {
+		options["score_mode"] = this.scoreMode
+	}
+	if this.boostMode != "" {
+		options["boost_mode"] = this.boostMode
+	}
+	if this.maxBoost != nil {
+		options["max_boost"] = *this.maxBoost
+	}
+	if this.minScore != nil {
+		options["min_score"] = *this.minScore
+	}
+	if this.boost != nil {
+		options["boost"] = *this.boost
+	}
+	return map[string]interface{}{"function_score": options}, nil
+}
+
+// ScoreFunction is a function of a FunctionScoreQuery.
+type ScoreFunction interface {
+	// Name is the key of the function in the query, e.g. "gauss".
+	Name() string
+	GetWeight() *float64
+	Source() (interface{}, error)
+}
+
+// -- weight --
+
+// WeightFactorFunction multiplies the score by a constant weight.
+type WeightFactorFunction struct {
+	weight float64
+}
+
+func NewWeightFactorFunction(weight float64) *WeightFactorFunction {
+	return &WeightFactorFunction{weight: weight}
+}
+
+func (this *WeightFactorFunction) Name() string {
+	return "weight"
+}
+
+func (this *WeightFactorFunction) GetWeight() *float64 {
+	return &this.weight
+}
+
+func (this *WeightFactorFunction) Source() (interface{}, error) {
+	return this.weight, nil
+}
+
+// -- field_value_factor --
+
+// FieldValueFactorFunction scores hits by the value of a numeric field.
+type FieldValueFactorFunction struct {
+	field    string
+	factor   *float64
+	modifier string
+	missing  *float64
+	weight   *float64
+}
+
+func NewFieldValueFactorFunction(field string) *FieldValueFactorFunction {
+	return &FieldValueFactorFunction{field: field}
+}
+
+func (this *FieldValueFactorFunction) Factor(factor float64) *FieldValueFactorFunction {
+	this.factor = &factor
+	return this
+}
+
+// Modifier sets the function applied to the field value, e.g. "log1p" or
+// "sqrt".
+func (this *FieldValueFactorFunction) Modifier(modifier string) *FieldValueFactorFunction {
+	this.modifier = modifier
+	return this
+}
+
+// Missing sets the value used for hits without the field.
+func (this *FieldValueFactorFunction) Missing(missing float64) *FieldValueFactorFunction {
+	this.missing = &missing
+	return this
+}
+
+func (this *FieldValueFactorFunction) Weight(weight float64) *FieldValueFactorFunction {
+	this.weight = &weight
+	return this
+}
+
+func (this *FieldValueFactorFunction) Name() string {
+	return "field_value_factor"
+}
+
+func (this *FieldValueFactorFunction) GetWeight() *float64 {
+	return this.weight
+}
+
+func (this *FieldValueFactorFunction) Source() (interface{}, error) {
+	if this.field == "" {
+		return nil, fmt.Errorf("field is required")
+	}
+	source := map[string]interface{}{"field": this.field}
+	if this.factor != nil {
+		source["factor"] = *this.factor
+	}
+	if this.modifier != "" {
+		source["modifier"] = this.modifier
+	}
+	if this.missing != nil {
+		source["missing"] = *this.missing
+	}
+	return source, nil
+}
+
+// -- gauss, exp, linear --
+
+// DecayFunction scores hits by the distance of a numeric, date or geo field
+// from an origin.
+type DecayFunction struct {
+	name           string
+	field          string
+	origin         interface{}
+	scale          interface{}
+	offset         interface{}
+	decay          *float64
+	multiValueMode string
+	weight         *float64
+}
+
+// NewGaussDecayFunction returns a decay function with a normal decay.
+func NewGaussDecayFunction(field string) *DecayFunction {
+	return &DecayFunction{name: "gauss", field: field}
+}
+
+// NewExpDecayFunction returns a decay function with an exponential decay.
+func NewExpDecayFunction(field string) *DecayFunction {
+	return &DecayFunction{name: "exp", field: field}
+}
+
+// NewLinearDecayFunction returns a decay function with a linear decay.
+func NewLinearDecayFunction(field string) *DecayFunction {
+	return &DecayFunction{name: "linear", field: field}
+}
+
+// Origin sets the point of the highest score, e.g. "now" for dates or
+// "31.23,121.47" for geo points.
+func (this *DecayFunction) Origin(origin interface{}) *DecayFunction {
+	this.origin = origin
+	return this
+}
+
+// Scale sets the distance from origin+offset at which the score drops to
+// the decay value, e.g. "10km" or "1h".
+func (this *DecayFunction) Scale(scale interface{}) *DecayFunction {
+	this.scale = scale
+	return this
+}
+
+// Offset sets the distance from origin within which hits get the full score.
+func (this *DecayFunction) Offset(offset interface{}) *DecayFunction {
+	this.offset = offset
+	return this
+}
+
+// Decay sets the score at distance scale; it defaults to 0.5.
+func (this *DecayFunction) Decay(decay float64) *DecayFunction {
+	this.decay = &decay
+	return this
+}
+
+// MultiValueMode sets which value of multi-valued fields is used: "min",
+// "max", "avg" or "sum".
+func (this *DecayFunction) MultiValueMode(mode string) *DecayFunction {
+	this.multiValueMode = mode
+	return this
+}
+
+func (this *DecayFunction) Weight(weight float64) *DecayFunction {
+	this.weight = &weight
+	return this
+}
+
+func (this *DecayFunction) Name() string {
+	return this.name
+}
+
+func (this *DecayFunction) GetWeight() *float64 {
+	return this.weight
+}
+
+func (this *DecayFunction) Source() (interface{}, error) {
+	if this.field == "" {
+		return nil, fmt.Errorf("field is required")
+	}
+	if this.scale == nil {
+		return nil, fmt.Errorf("scale is required")
+	}
+	params := map[string]interface{}{"scale": this.scale}
+	if this.origin != nil {
+		params["origin"] = this.origin
+	}
+	if this.offset != nil {
+		params["offset"] = this.offset
+	}
+	if this.decay != nil {
+		params["decay"] = *this.decay
+	}
+	source := map[string]interface{}{this.field: params}
+	if this.multiValueMode != "" {
+		source["multi_value_mode"] = this.multiValueMode
+	}
+	return source, nil
+}
+
+// -- script_score --
+
+// ScriptScoreFunction scores hits with a script.
+type ScriptScoreFunction struct {
+	script interface{}
+	weight *float64
+}
+
+// NewScriptScoreFunction returns a function scoring with script, either a
+// string or a script object like map[string]interface{}{"source": ..., "params": ...}.
+func NewScriptScoreFunction(script interface{}) *ScriptScoreFunction {
+	return &ScriptScoreFunction{script: script}
+}
+
+func (this *ScriptScoreFunction) Weight(weight float64) *ScriptScoreFunction {
+	this.weight = &weight
+	return this
+}
+
+func (this *ScriptScoreFunction) Name() string {
+	return "script_score"
+}
+
+func (this *ScriptScoreFunction) GetWeight() *float64 {
+	return this.weight
+}
+
+func (this *ScriptScoreFunction) Source() (interface{}, error) {
+	if this.script == nil {
+		return nil, fmt.Errorf("script is required")
+	}
+	return map[string]interface{}{"script": this.script}, nil
+}
@@ -0,0 +1,53 @@
+package go_elasticsearch
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFunctionScoreCollapse(t *testing.T) {
+	client, _ := NewClient()
+	query := client.Search("md_dispatch").Limit(-1).
+		AndWhere("in", "F_Status", "open").
+		FunctionScore(NewFunctionScoreQuery().
+			AddScoreFunc(NewGaussDecayFunction("F_Location").Origin("31.23,121.47").Scale("10km")).
+			Add(client.Search().AndWhere("in", "F_Priority", "high"), NewWeightFactorFunction(2)).
+			AddScoreFunc(NewFieldValueFactorFunction("F_Rating").Modifier("log1p").Missing(1)).
+			ScoreMode("multiply")).
+		Collapse(NewCollapse("F_CarrierId").InnerHit(NewInnerHit("latest").Size(3)))
+
+	builder := QueryBuilder{}
+	body, err := builder.Build(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(body)
+	expected := `{"aggregations":{},"collapse":{"field":"F_CarrierId","inner_hits":{"name":"latest","size":3}},` +
+		`"query":{"function_score":{"functions":[` +
+		`{"gauss":{"F_Location":{"origin":"31.23,121.47","scale":"10km"}}},` +
+		`{"filter":{"bool":{"must":[{"term":{"F_Priority":"high"}}]}},"weight":2},` +
+		`{"field_value_factor":{"field":"F_Rating","missing":1,"modifier":"log1p"}}],` +
+		`"query":{"bool":{"must":[{"term":{"F_Status":"open"}}]}},"score_mode":"multiply"}}}`
+	if string(data) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, string(data))
+	}
+}
+
+func TestFunctionScoreRescore(t *testing.T) {
+	client, _ := NewClient()
+	query := client.Search("md_dispatch").Limit(-1).
+		AndWhere("in", "F_Status", "open").
+		Rescore(NewRescore(NewFunctionScoreQuery().AddScoreFunc(NewScriptScoreFunction("doc['F_Rating'].value"))).WindowSize(50))
+
+	builder := QueryBuilder{}
+	body, err := builder.Build(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(body)
+	expected := `{"aggregations":{},"query":{"bool":{"must":[{"term":{"F_Status":"open"}}]}},` +
+		`"rescore":[{"query":{"rescore_query":{"function_score":{"functions":[{"script_score":{"script":"doc['F_Rating'].value"}}]}}},"window_size":50}]}`
+	if string(data) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, string(data))
+	}
+}
@@ -0,0 +1,170 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// GetService gets a document by id. A missing document is returned as
+// *Error with status 404, see IsNotFound.
+//
+//	doc, err := client.Get("md_fin_waybill").Id("W123").Do(ctx)
+//	err = doc.Decode(&waybill)
+type GetService struct {
+	client         *Client
+	index          string
+	typ            string
+	id             string
+	routing        string
+	preference     string
+	realtime       *bool
+	refresh        bool
+	source         *bool
+	sourceIncludes []string
+	sourceExcludes []string
+	storedFields   []string
+	version        *int64
+}
+
+func NewGetService(c *Client) *GetService {
+	return &GetService{
+		client: c,
+	}
+}
+
+func (this *Client) Get(index string) *GetService {
+	return NewGetService(this).Index(index)
+}
+
+func (this *GetService) Index(index string) *GetService {
+	this.index = index
+	return this
+}
+
+// Type sets the type of the document, for Elasticsearch 6.x.
+func (this *GetService) Type(typ string) *GetService {
+	this.typ = typ
+	return this
+}
+
+func (this *GetService) Id(id string) *GetService {
+	this.id = id
+	return this
+}
+
+func (this *GetService) Routing(routing string) *GetService {
+	this.routing = routing
+	return this
+}
+
+func (this *GetService) Preference(preference string) *GetService {
+	this.preference = preference
+	return this
+}
+
+// Realtime set to false gets the document from the last refreshed state of
+// the index instead of the transaction log.
+func (this *GetService) Realtime(realtime bool) *GetService {
+	this.realtime = &realtime
+	return this
+}
+
+// Refresh refreshes the shard before getting the document.
+func (this *GetService) Refresh(refresh bool) *GetService {
+	this.refresh = refresh
+	return this
+}
+
+// FetchSource set to false leaves out the source of the document.
+func (this *GetService) FetchSource(fetchSource bool) *GetService {
+	this.source = &fetchSource
+	return this
+}
+
+// SourceIncludes returns only the given fields of the source; wildcards are
+// allowed.
+func (this *GetService) SourceIncludes(fields ...string) *GetService {
+	this.sourceIncludes = append(this.sourceIncludes, fields...)
+	return this
+}
+
+// SourceExcludes leaves out the given fields of the source.
+func (this *GetService) SourceExcludes(fields ...string) *GetService {
+	this.sourceExcludes = append(this.sourceExcludes, fields...)
+	return this
+}
+
+// StoredFields returns the given stored fields in GetResult.Fields.
+func (this *GetService) StoredFields(fields ...string) *GetService {
+	this.storedFields = append(this.storedFields, fields...)
+	return this
+}
+
+// Version fails the request with status 409 if the document has another
+// version.
+func (this *GetService) Version(version int64) *GetService {
+	this.version = &version
+	return this
+}
+
+func (this *GetService) buildUrl() (string, url.Values, error) {
+	path, err := documentPath(this.index, this.typ, this.id)
+	if err != nil {
+		return "", url.Values{}, err
+	}
+	params := url.Values{}
+	if this.routing != "" {
+		params.Set("routing", this.routing)
+	}
+	if this.preference != "" {
+		params.Set("preference", this.preference)
+	}
+	if this.realtime != nil {
+		params.Set("realtime", strconv.FormatBool(*this.realtime))
+	}
+	if this.refresh {
+		params.Set("refresh", "true")
+	}
+	if this.source != nil {
+		params.Set("_source", strconv.FormatBool(*this.source))
+	}
+	if len(this.sourceIncludes) > 0 {
+		params.Set("_source_includes", strings.Join(this.sourceIncludes, ","))
+	}
+	if len(this.sourceExcludes) > 0 {
+		params.Set("_source_excludes", strings.Join(this.sourceExcludes, ","))
+	}
+	if len(this.storedFields) > 0 {
+		params.Set("stored_fields", strings.Join(this.storedFields, ","))
+	}
+	if this.version != nil {
+		params.Set("version", strconv.FormatInt(*this.version, 10))
+	}
+	return path, params, nil
+}
+
+func (this *GetService) Do(ctx context.Context) (*GetResult, error) {
+	if this.index == "" {
+		return nil, fmt.Errorf("index is required")
+	}
+	if this.id == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+	path, params, err := this.buildUrl()
+	if err != nil {
+		return nil, err
+	}
+	response, err := this.client.httpRequest(ctx, "GET", path, params, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(GetResult)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -1 +1,3 @@
-module "github.com/wh5231/go-elasticsearch"
\ No newline at end of file
+module "github.com/wh5231/go-elasticsearch"
+
+go 1.18
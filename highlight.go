@@ -0,0 +1,228 @@
+package go_elasticsearch
+
+// Highlight configures highlighting of the matching parts of hits. The
+// options set on Highlight apply to all fields unless overridden per field.
+//
+//	client.Search("md_fin_waybill").AndWhere("in", "F_O_CustomerName", "acme").Highlight(
+//		NewHighlight().PreTags("<em>").PostTags("</em>").Fields(
+//			NewHighlighterField("F_O_CustomerName").NumberOfFragments(0),
+//			NewHighlighterField("F_Remark").FragmentSize(100),
+//		),
+//	)
+type Highlight struct {
+	fields []*HighlighterField
+	highlighterOptions
+	order   string
+	encoder string
+}
+
+func NewHighlight() *Highlight {
+	return &Highlight{
+		fields: make([]*HighlighterField, 0),
+	}
+}
+
+// Fields adds the fields to highlight.
+func (this *Highlight) Fields(fields ...*HighlighterField) *Highlight {
+	this.fields = append(this.fields, fields...)
+	return this
+}
+
+func (this *Highlight) PreTags(tags ...string) *Highlight {
+	this.preTags = tags
+	return this
+}
+
+func (this *Highlight) PostTags(tags ...string) *Highlight {
+	this.postTags = tags
+	return this
+}
+
+// FragmentSize sets the size of the highlighted fragments in characters.
+func (this *Highlight) FragmentSize(fragmentSize int) *Highlight {
+	this.fragmentSize = &fragmentSize
+	return this
+}
+
+// NumberOfFragments sets the maximum number of fragments returned. With 0,
+// the whole field content is highlighted and returned.
+func (this *Highlight) NumberOfFragments(numberOfFragments int) *Highlight {
+	this.numberOfFragments = &numberOfFragments
+	return this
+}
+
+// Type sets the highlighter to use: "unified", "plain" or "fvh".
+func (this *Highlight) Type(highlighterType string) *Highlight {
+	this.highlighterType = highlighterType
+	return this
+}
+
+// HighlightQuery highlights the matches of another query than the search
+// query. It is either a *Query, whose where conditions are used, a typed
+// query such as *FunctionScoreQuery, or a raw query such as
+// map[string]interface{}{"match": ...}.
+func (this *Highlight) HighlightQuery(query interface{}) *Highlight {
+	this.highlightQuery = query
+	return this
+}
+
+func (this *Highlight) RequireFieldMatch(requireFieldMatch bool) *Highlight {
+	this.requireFieldMatch = &requireFieldMatch
+	return this
+}
+
+// Order sets the order of the fragments; "score" sorts them by relevance.
+func (this *Highlight) Order(order string) *Highlight {
+	this.order = order
+	return this
+}
+
+// Encoder sets the encoder of the fragments; "html" escapes the content.
+func (this *Highlight) Encoder(encoder string) *Highlight {
+	this.encoder = encoder
+	return this
+}
+
+func (this *Highlight) Source() (interface{}, error) {
+	source, err := this.highlighterOptions.source()
+	if err != nil {
+		return nil, err
+	}
+	if this.order != "" {
+		source["order"] = this.order
+	}
+	if this.encoder != "" {
+		source["encoder"] = this.encoder
+	}
+	fields := make(map[string]interface{}, len(this.fields))
+	for _, field := range this.fields {
+		options, err := field.Source()
+		if err != nil {
+			return nil, err
+		}
+		fields[field.name] = options
+	}
+	source["fields"] = fields
+	return source, nil
+}
+
+// HighlighterField is a field to highlight, with options overriding the
+// ones set on Highlight.
+type HighlighterField struct {
+	name string
+	highlighterOptions
+	matchedFields []string
+	noMatchSize   *int
+}
+
+func NewHighlighterField(name string) *HighlighterField {
+	return &HighlighterField{
+		name: name,
+	}
+}
+
+func (this *HighlighterField) PreTags(tags ...string) *HighlighterField {
+	this.preTags = tags
+	return this
+}
+
+func (this *HighlighterField) PostTags(tags ...string) *HighlighterField {
+	this.postTags = tags
+	return this
+}
+
+func (this *HighlighterField) FragmentSize(fragmentSize int) *HighlighterField {
+	this.fragmentSize = &fragmentSize
+	return this
+}
+
+func (this *HighlighterField) NumberOfFragments(numberOfFragments int) *HighlighterField {
+	this.numberOfFragments = &numberOfFragments
+	return this
+}
+
+func (this *HighlighterField) Type(highlighterType string) *HighlighterField {
+	this.highlighterType = highlighterType
+	return this
+}
+
+func (this *HighlighterField) HighlightQuery(query interface{}) *HighlighterField {
+	this.highlightQuery = query
+	return this
+}
+
+func (this *HighlighterField) RequireFieldMatch(requireFieldMatch bool) *HighlighterField {
+	this.requireFieldMatch = &requireFieldMatch
+	return this
+}
+
+// MatchedFields combines the matches of several fields into the highlight
+// of this one. It requires the "fvh" highlighter.
+func (this *HighlighterField) MatchedFields(fields ...string) *HighlighterField {
+	this.matchedFields = fields
+	return this
+}
+
+// NoMatchSize returns this many characters from the start of the field if
+// nothing matched.
+func (this *HighlighterField) NoMatchSize(noMatchSize int) *HighlighterField {
+	this.noMatchSize = &noMatchSize
+	return this
+}
+
+func (this *HighlighterField) Source() (interface{}, error) {
+	source, err := this.highlighterOptions.source()
+	if err != nil {
+		return nil, err
+	}
+	if len(this.matchedFields) > 0 {
+		source["matched_fields"] = this.matchedFields
+	}
+	if this.noMatchSize != nil {
+		source["no_match_size"] = *this.noMatchSize
+	}
+	return source, nil
+}
+
+// highlighterOptions are the options shared by Highlight and HighlighterField.
+type highlighterOptions struct {
+	preTags           []string
+	postTags          []string
+	fragmentSize      *int
+	numberOfFragments *int
+	highlighterType   string
+	highlightQuery    interface{}
+	requireFieldMatch *bool
+}
+
+func (this *highlighterOptions) source() (map[string]interface{}, error) {
+	source := make(map[string]interface{})
+	if len(this.preTags) > 0 {
+		source["pre_tags"] = this.preTags
+	}
+	if len(this.postTags) > 0 {
+		source["post_tags"] = this.postTags
+	}
+	if this.fragmentSize != nil {
+		source["fragment_size"] = *this.fragmentSize
+	}
+	if this.numberOfFragments != nil {
+		source["number_of_fragments"] = *this.numberOfFragments
+	}
+	if this.highlighterType != "" {
+		source["type"] = this.highlighterType
+	}
+	if this.requireFieldMatch != nil {
+		source["require_field_match"] = *this.requireFieldMatch
+	}
+	if this.highlightQuery != nil {
+		query, err := buildQueryValue(this.highlightQuery)
+		if err != nil {
+			return nil, err
+		}
+		if query != nil {
+			source["highlight_query"] = query
+		}
+	}
+	return source, nil
+}
@@ -0,0 +1,50 @@
+package go_elasticsearch
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHighlightSource(t *testing.T) {
+	client, _ := NewClient()
+	builder := QueryBuilder{}
+	body, err := builder.Build(client.Search("md_fin_waybill").Highlight(
+		NewHighlight().PreTags("<em>").PostTags("</em>").
+			HighlightQuery(client.Search().AndWhere("in", "F_O_CustomerName", "acme")).
+			Fields(
+				NewHighlighterField("F_O_CustomerName").NumberOfFragments(0),
+				NewHighlighterField("F_Remark").FragmentSize(100).
+					HighlightQuery(map[string]interface{}{"match": map[string]interface{}{"F_Remark": "urgent"}}),
+			),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(body["highlight"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"fields":{"F_O_CustomerName":{"number_of_fragments":0},` +
+		`"F_Remark":{"fragment_size":100,"highlight_query":{"match":{"F_Remark":"urgent"}}}},` +
+		`"highlight_query":{"bool":{"must":[{"term":{"F_O_CustomerName":"acme"}}]}},` +
+		`"post_tags":["\u003c/em\u003e"],"pre_tags":["\u003cem\u003e"]}`
+	if string(data) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, string(data))
+	}
+}
+
+func TestSearchHitHighlight(t *testing.T) {
+	body := `{"hits": {"hits": [{"_id": "1", "_index": "md_fin_customer",
+		"highlight": {"F_O_CustomerName": ["<em>acme</em> ltd"], "F_Remark": ["ships <em>acme</em>", "to <em>acme</em>"]}}]}}`
+	result := new(SearchResult)
+	if err := json.Unmarshal([]byte(body), result); err != nil {
+		t.Fatal(err)
+	}
+	highlight := result.Hits.Hits[0].Highlight
+	if len(highlight["F_O_CustomerName"]) != 1 || highlight["F_O_CustomerName"][0] != "<em>acme</em> ltd" {
+		t.Errorf("unexpected highlight %v", highlight)
+	}
+	if len(highlight["F_Remark"]) != 2 {
+		t.Errorf("expected 2 fragments of F_Remark, got %v", highlight["F_Remark"])
+	}
+}
@@ -0,0 +1,186 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// IndexService adds or replaces a document.
+//
+//	response, err := client.Index("md_fin_waybill").Id("W123").BodyJson(waybill).Refresh("wait_for").Do(ctx)
+type IndexService struct {
+	client        *Client
+	index         string
+	typ           string
+	id            string
+	body          interface{}
+	routing       string
+	refresh       string
+	opType        string
+	pipeline      string
+	timeout       string
+	version       *int64
+	versionType   string
+	ifSeqNo       *int64
+	ifPrimaryTerm *int64
+}
+
+func NewIndexService(c *Client) *IndexService {
+	return &IndexService{
+		client: c,
+	}
+}
+
+func (this *Client) Index(index string) *IndexService {
+	return NewIndexService(this).Index(index)
+}
+
+func (this *IndexService) Index(index string) *IndexService {
+	this.index = index
+	return this
+}
+
+// Type sets the type of the document, for Elasticsearch 6.x.
+func (this *IndexService) Type(typ string) *IndexService {
+	this.typ = typ
+	return this
+}
+
+// Id sets the id of the document. Without id, Elasticsearch generates one.
+func (this *IndexService) Id(id string) *IndexService {
+	this.id = id
+	return this
+}
+
+// BodyJson sets the document, encoded with json.Marshal.
+func (this *IndexService) BodyJson(body interface{}) *IndexService {
+	this.body = body
+	return this
+}
+
+// BodyString sets the document as JSON.
+func (this *IndexService) BodyString(body string) *IndexService {
+	this.body = json.RawMessage(body)
+	return this
+}
+
+func (this *IndexService) Routing(routing string) *IndexService {
+	this.routing = routing
+	return this
+}
+
+// Refresh sets when the change is made visible to search: "true",
+// "wait_for" or "false".
+func (this *IndexService) Refresh(refresh string) *IndexService {
+	this.refresh = refresh
+	return this
+}
+
+// OpType sets "create" to fail if the document exists already.
+func (this *IndexService) OpType(opType string) *IndexService {
+	this.opType = opType
+	return this
+}
+
+func (this *IndexService) Pipeline(pipeline string) *IndexService {
+	this.pipeline = pipeline
+	return this
+}
+
+func (this *IndexService) Timeout(timeout string) *IndexService {
+	this.timeout = timeout
+	return this
+}
+
+// Version sets the expected version of the document, see VersionType.
+func (this *IndexService) Version(version int64) *IndexService {
+	this.version = &version
+	return this
+}
+
+// VersionType sets "external" or "external_gte" to use versions maintained
+// outside Elasticsearch.
+func (this *IndexService) VersionType(versionType string) *IndexService {
+	this.versionType = versionType
+	return this
+}
+
+// IfSeqNo only writes the document if its sequence number is seqNo. A
+// mismatch is returned as *Error with status 409, see IsConflict.
+func (this *IndexService) IfSeqNo(seqNo int64) *IndexService {
+	this.ifSeqNo = &seqNo
+	return this
+}
+
+// IfPrimaryTerm only writes the document if its primary term is
+// primaryTerm; use it together with IfSeqNo.
+func (this *IndexService) IfPrimaryTerm(primaryTerm int64) *IndexService {
+	this.ifPrimaryTerm = &primaryTerm
+	return this
+}
+
+func (this *IndexService) buildUrl() (string, url.Values, error) {
+	path, err := documentPath(this.index, this.typ, this.id)
+	if err != nil {
+		return "", url.Values{}, err
+	}
+	params := url.Values{}
+	if this.routing != "" {
+		params.Set("routing", this.routing)
+	}
+	if this.refresh != "" {
+		params.Set("refresh", this.refresh)
+	}
+	if this.opType != "" {
+		params.Set("op_type", this.opType)
+	}
+	if this.pipeline != "" {
+		params.Set("pipeline", this.pipeline)
+	}
+	if this.timeout != "" {
+		params.Set("timeout", this.timeout)
+	}
+	if this.version != nil {
+		params.Set("version", strconv.FormatInt(*this.version, 10))
+	}
+	if this.versionType != "" {
+		params.Set("version_type", this.versionType)
+	}
+	if this.ifSeqNo != nil {
+		params.Set("if_seq_no", strconv.FormatInt(*this.ifSeqNo, 10))
+	}
+	if this.ifPrimaryTerm != nil {
+		params.Set("if_primary_term", strconv.FormatInt(*this.ifPrimaryTerm, 10))
+	}
+	return path, params, nil
+}
+
+// Do writes the document. A non-2xx response is returned as *Error.
+func (this *IndexService) Do(ctx context.Context) (*IndexResponse, error) {
+	if this.index == "" {
+		return nil, fmt.Errorf("index is required")
+	}
+	if this.body == nil {
+		return nil, fmt.Errorf("body is required")
+	}
+	path, params, err := this.buildUrl()
+	if err != nil {
+		return nil, err
+	}
+	method := "PUT"
+	if this.id == "" {
+		method = "POST"
+	}
+	response, err := this.client.httpRequest(ctx, method, path, params, this.body, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(IndexResponse)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,36 @@
+package go_elasticsearch
+
+import (
+	"github.com/wh5231/go-elasticsearch/uritemplates"
+	"strings"
+)
+
+// indicesPath builds the path of an endpoint on indices, e.g.
+// "/{index}/_refresh", or "/_refresh" for all indices.
+func indicesPath(index []string, endpoint string) (string, error) {
+	if len(index) == 0 {
+		return "/" + endpoint, nil
+	}
+	path, err := uritemplates.Expand("/{index}", map[string]string{
+		"index": strings.Join(index, ","),
+	})
+	if err != nil {
+		return "", err
+	}
+	// the endpoint is not escaped, it may hold slashes like "_cache/clear"
+	return path + "/" + endpoint, nil
+}
+
+// IndicesResponse is the response of index management requests that are
+// acknowledged by the master node, e.g. create, delete, open and close.
+type IndicesResponse struct {
+	Acknowledged       bool   `json:"acknowledged"`
+	ShardsAcknowledged bool   `json:"shards_acknowledged,omitempty"`
+	Index              string `json:"index,omitempty"`
+}
+
+// IndicesShardsResponse is the response of index management requests
+// executed on the shards, e.g. refresh, flush, forcemerge and clear cache.
+type IndicesShardsResponse struct {
+	Shards *ShardsInfo `json:"_shards"`
+}
@@ -0,0 +1,148 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/wh5231/go-elasticsearch/uritemplates"
+	"net/url"
+)
+
+// IndicesCreateService creates an index with settings, mappings and aliases.
+//
+//	response, err := client.CreateIndex("md_fin_waybill_202003").
+//		Settings(map[string]interface{}{"number_of_shards": 3, "number_of_replicas": 1}).
+//		Mappings(map[string]interface{}{"properties": map[string]interface{}{
+//			"F_OrderTime": map[string]interface{}{"type": "date"},
+//		}}).
+//		Alias("md_fin_waybill", nil).
+//		Do(ctx)
+type IndicesCreateService struct {
+	client              *Client
+	index               string
+	body                interface{}
+	settings            map[string]interface{}
+	mappings            interface{}
+	aliases             map[string]interface{}
+	waitForActiveShards string
+	timeout             string
+}
+
+func NewIndicesCreateService(c *Client) *IndicesCreateService {
+	return &IndicesCreateService{
+		client: c,
+	}
+}
+
+func (this *Client) CreateIndex(index string) *IndicesCreateService {
+	return NewIndicesCreateService(this).Index(index)
+}
+
+func (this *IndicesCreateService) Index(index string) *IndicesCreateService {
+	this.index = index
+	return this
+}
+
+// BodyJson sets the whole body of the request, with "settings", "mappings"
+// and "aliases", in place of Settings, Mappings and Alias.
+func (this *IndicesCreateService) BodyJson(body interface{}) *IndicesCreateService {
+	this.body = body
+	return this
+}
+
+// BodyString sets the whole body of the request as JSON.
+func (this *IndicesCreateService) BodyString(body string) *IndicesCreateService {
+	this.body = json.RawMessage(body)
+	return this
+}
+
+// Settings adds index settings, e.g. "number_of_shards".
+func (this *IndicesCreateService) Settings(settings map[string]interface{}) *IndicesCreateService {
+	if this.settings == nil {
+		this.settings = make(map[string]interface{})
+	}
+	for name, value := range settings {
+		this.settings[name] = value
+	}
+	return this
+}
+
+// Mappings sets the mappings of the index, e.g. with "properties".
+func (this *IndicesCreateService) Mappings(mappings interface{}) *IndicesCreateService {
+	this.mappings = mappings
+	return this
+}
+
+// Alias adds an alias of the index, with options like "filter" or
+// "is_write_index", or nil.
+func (this *IndicesCreateService) Alias(name string, options map[string]interface{}) *IndicesCreateService {
+	if this.aliases == nil {
+		this.aliases = make(map[string]interface{})
+	}
+	if options == nil {
+		options = make(map[string]interface{})
+	}
+	this.aliases[name] = options
+	return this
+}
+
+// WaitForActiveShards sets the number of shard copies that must be active
+// before returning, e.g. "1" or "all".
+func (this *IndicesCreateService) WaitForActiveShards(waitForActiveShards string) *IndicesCreateService {
+	this.waitForActiveShards = waitForActiveShards
+	return this
+}
+
+func (this *IndicesCreateService) Timeout(timeout string) *IndicesCreateService {
+	this.timeout = timeout
+	return this
+}
+
+// Body returns the body of the request.
+func (this *IndicesCreateService) Body() interface{} {
+	if this.body != nil {
+		return this.body
+	}
+	body := make(map[string]interface{})
+	if len(this.settings) > 0 {
+		body["settings"] = this.settings
+	}
+	if this.mappings != nil {
+		body["mappings"] = this.mappings
+	}
+	if len(this.aliases) > 0 {
+		body["aliases"] = this.aliases
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	return body
+}
+
+func (this *IndicesCreateService) Do(ctx context.Context) (*IndicesResponse, error) {
+	if this.index == "" {
+		return nil, fmt.Errorf("index is required")
+	}
+	path, err := uritemplates.Expand("/{index}", map[string]string{
+		"index": this.index,
+	})
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	if this.waitForActiveShards != "" {
+		params.Set("wait_for_active_shards", this.waitForActiveShards)
+	}
+	if this.timeout != "" {
+		params.Set("timeout", this.timeout)
+	}
+	response, err := this.client.httpRequest(ctx, "PUT", path, params, this.Body(), false)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(IndicesResponse)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
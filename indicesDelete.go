@@ -0,0 +1,73 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/wh5231/go-elasticsearch/uritemplates"
+	"net/url"
+	"strings"
+)
+
+// IndicesDeleteService deletes indices. A missing index is returned as
+// *Error with status 404 unless IgnoreUnavailable is set.
+type IndicesDeleteService struct {
+	client            *Client
+	index             []string
+	ignoreUnavailable bool
+	timeout           string
+}
+
+func NewIndicesDeleteService(c *Client) *IndicesDeleteService {
+	return &IndicesDeleteService{
+		client: c,
+		index:  make([]string, 0),
+	}
+}
+
+func (this *Client) DeleteIndex(index ...string) *IndicesDeleteService {
+	return NewIndicesDeleteService(this).Index(index...)
+}
+
+func (this *IndicesDeleteService) Index(index ...string) *IndicesDeleteService {
+	this.index = append(this.index, index...)
+	return this
+}
+
+func (this *IndicesDeleteService) IgnoreUnavailable(ignoreUnavailable bool) *IndicesDeleteService {
+	this.ignoreUnavailable = ignoreUnavailable
+	return this
+}
+
+func (this *IndicesDeleteService) Timeout(timeout string) *IndicesDeleteService {
+	this.timeout = timeout
+	return this
+}
+
+func (this *IndicesDeleteService) Do(ctx context.Context) (*IndicesResponse, error) {
+	if len(this.index) == 0 {
+		return nil, fmt.Errorf("index is required")
+	}
+	path, err := uritemplates.Expand("/{index}", map[string]string{
+		"index": strings.Join(this.index, ","),
+	})
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	if this.ignoreUnavailable {
+		params.Set("ignore_unavailable", "true")
+	}
+	if this.timeout != "" {
+		params.Set("timeout", this.timeout)
+	}
+	response, err := this.client.httpRequest(ctx, "DELETE", path, params, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(IndicesResponse)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,29 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"github.com/wh5231/go-elasticsearch/uritemplates"
+	"strings"
+)
+
+// IndexExists returns whether all the given indices, or aliases, exist.
+func (this *Client) IndexExists(ctx context.Context, index ...string) (bool, error) {
+	if len(index) == 0 {
+		return false, fmt.Errorf("index is required")
+	}
+	path, err := uritemplates.Expand("/{index}", map[string]string{
+		"index": strings.Join(index, ","),
+	})
+	if err != nil {
+		return false, err
+	}
+	_, err = this.httpRequest(ctx, "HEAD", path, nil, nil, false)
+	if IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,216 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Refresh makes the changes of the given indices, or of all indices,
+// visible to search.
+func (this *Client) Refresh(ctx context.Context, index ...string) (*IndicesShardsResponse, error) {
+	path, err := indicesPath(index, "_refresh")
+	if err != nil {
+		return nil, err
+	}
+	return this.indicesShardsRequest(ctx, path, nil)
+}
+
+// indicesShardsRequest posts to an index management endpoint executed on
+// the shards.
+func (this *Client) indicesShardsRequest(ctx context.Context, path string, params url.Values) (*IndicesShardsResponse, error) {
+	response, err := this.httpRequest(ctx, "POST", path, params, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(IndicesShardsResponse)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// IndicesFlushService writes the transaction log of indices to disk.
+type IndicesFlushService struct {
+	client        *Client
+	index         []string
+	force         bool
+	waitIfOngoing *bool
+}
+
+func NewIndicesFlushService(c *Client) *IndicesFlushService {
+	return &IndicesFlushService{
+		client: c,
+		index:  make([]string, 0),
+	}
+}
+
+func (this *Client) Flush(index ...string) *IndicesFlushService {
+	return NewIndicesFlushService(this).Index(index...)
+}
+
+func (this *IndicesFlushService) Index(index ...string) *IndicesFlushService {
+	this.index = append(this.index, index...)
+	return this
+}
+
+// Force flushes even if there are no changes to commit.
+func (this *IndicesFlushService) Force(force bool) *IndicesFlushService {
+	this.force = force
+	return this
+}
+
+// WaitIfOngoing set to false returns an error instead of waiting if a flush
+// is running already.
+func (this *IndicesFlushService) WaitIfOngoing(waitIfOngoing bool) *IndicesFlushService {
+	this.waitIfOngoing = &waitIfOngoing
+	return this
+}
+
+func (this *IndicesFlushService) Do(ctx context.Context) (*IndicesShardsResponse, error) {
+	path, err := indicesPath(this.index, "_flush")
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	if this.force {
+		params.Set("force", "true")
+	}
+	if this.waitIfOngoing != nil {
+		params.Set("wait_if_ongoing", strconv.FormatBool(*this.waitIfOngoing))
+	}
+	return this.client.indicesShardsRequest(ctx, path, params)
+}
+
+// IndicesForcemergeService merges the segments of indices, e.g. of monthly
+// indices that are no longer written to.
+//
+//	client.Forcemerge("md_fin_waybill_202002").MaxNumSegments(1).Do(ctx)
+type IndicesForcemergeService struct {
+	client             *Client
+	index              []string
+	maxNumSegments     int
+	onlyExpungeDeletes bool
+	flush              *bool
+}
+
+func NewIndicesForcemergeService(c *Client) *IndicesForcemergeService {
+	return &IndicesForcemergeService{
+		client: c,
+		index:  make([]string, 0),
+	}
+}
+
+func (this *Client) Forcemerge(index ...string) *IndicesForcemergeService {
+	return NewIndicesForcemergeService(this).Index(index...)
+}
+
+func (this *IndicesForcemergeService) Index(index ...string) *IndicesForcemergeService {
+	this.index = append(this.index, index...)
+	return this
+}
+
+// MaxNumSegments sets the number of segments per shard to merge into.
+func (this *IndicesForcemergeService) MaxNumSegments(maxNumSegments int) *IndicesForcemergeService {
+	this.maxNumSegments = maxNumSegments
+	return this
+}
+
+// OnlyExpungeDeletes only merges segments with deleted documents.
+func (this *IndicesForcemergeService) OnlyExpungeDeletes(onlyExpungeDeletes bool) *IndicesForcemergeService {
+	this.onlyExpungeDeletes = onlyExpungeDeletes
+	return this
+}
+
+// Flush set to false does not flush the indices after merging.
+func (this *IndicesForcemergeService) Flush(flush bool) *IndicesForcemergeService {
+	this.flush = &flush
+	return this
+}
+
+func (this *IndicesForcemergeService) Do(ctx context.Context) (*IndicesShardsResponse, error) {
+	path, err := indicesPath(this.index, "_forcemerge")
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	if this.maxNumSegments > 0 {
+		params.Set("max_num_segments", strconv.Itoa(this.maxNumSegments))
+	}
+	if this.onlyExpungeDeletes {
+		params.Set("only_expunge_deletes", "true")
+	}
+	if this.flush != nil {
+		params.Set("flush", strconv.FormatBool(*this.flush))
+	}
+	return this.client.indicesShardsRequest(ctx, path, params)
+}
+
+// IndicesClearCacheService clears the caches of indices. Without options
+// all caches are cleared.
+type IndicesClearCacheService struct {
+	client    *Client
+	index     []string
+	query     bool
+	fielddata bool
+	request   bool
+	fields    []string
+}
+
+func NewIndicesClearCacheService(c *Client) *IndicesClearCacheService {
+	return &IndicesClearCacheService{
+		client: c,
+		index:  make([]string, 0),
+	}
+}
+
+func (this *Client) ClearCache(index ...string) *IndicesClearCacheService {
+	return NewIndicesClearCacheService(this).Index(index...)
+}
+
+func (this *IndicesClearCacheService) Index(index ...string) *IndicesClearCacheService {
+	this.index = append(this.index, index...)
+	return this
+}
+
+// Query clears the query cache.
+func (this *IndicesClearCacheService) Query(query bool) *IndicesClearCacheService {
+	this.query = query
+	return this
+}
+
+// Fielddata clears the fielddata cache, of the given fields only if set.
+func (this *IndicesClearCacheService) Fielddata(fielddata bool, fields ...string) *IndicesClearCacheService {
+	this.fielddata = fielddata
+	this.fields = append(this.fields, fields...)
+	return this
+}
+
+// Request clears the shard request cache.
+func (this *IndicesClearCacheService) Request(request bool) *IndicesClearCacheService {
+	this.request = request
+	return this
+}
+
+func (this *IndicesClearCacheService) Do(ctx context.Context) (*IndicesShardsResponse, error) {
+	path, err := indicesPath(this.index, "_cache/clear")
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	if this.query {
+		params.Set("query", "true")
+	}
+	if this.fielddata {
+		params.Set("fielddata", "true")
+	}
+	if this.request {
+		params.Set("request", "true")
+	}
+	if len(this.fields) > 0 {
+		params.Set("fields", strings.Join(this.fields, ","))
+	}
+	return this.client.indicesShardsRequest(ctx, path, params)
+}
@@ -0,0 +1,38 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// OpenIndex opens closed indices, making them available for reads and
+// writes again.
+func (this *Client) OpenIndex(ctx context.Context, index ...string) (*IndicesResponse, error) {
+	return this.openOrCloseIndex(ctx, "_open", index)
+}
+
+// CloseIndex closes indices, e.g. old monthly indices that are kept but no
+// longer searched. Closed indices use no resources besides disk space.
+func (this *Client) CloseIndex(ctx context.Context, index ...string) (*IndicesResponse, error) {
+	return this.openOrCloseIndex(ctx, "_close", index)
+}
+
+func (this *Client) openOrCloseIndex(ctx context.Context, endpoint string, index []string) (*IndicesResponse, error) {
+	if len(index) == 0 {
+		return nil, fmt.Errorf("index is required")
+	}
+	path, err := indicesPath(index, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	response, err := this.httpRequest(ctx, "POST", path, nil, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(IndicesResponse)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,55 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// IndexSettings are the settings of an index, nested as returned by
+// Elasticsearch, e.g. Settings["index"]["number_of_replicas"].
+type IndexSettings struct {
+	Settings map[string]interface{} `json:"settings"`
+}
+
+// GetIndexSettings returns the settings of the given indices, or of all
+// indices, by index name.
+func (this *Client) GetIndexSettings(ctx context.Context, index ...string) (map[string]*IndexSettings, error) {
+	path, err := indicesPath(index, "_settings")
+	if err != nil {
+		return nil, err
+	}
+	response, err := this.httpRequest(ctx, "GET", path, nil, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]*IndexSettings)
+	if err := json.Unmarshal(response.Body, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// PutIndexSettings updates the dynamic settings of the given indices, e.g.
+//
+//	client.PutIndexSettings(ctx, map[string]interface{}{
+//		"index": map[string]interface{}{"number_of_replicas": 0, "refresh_interval": "-1"},
+//	}, "md_fin_waybill_202003")
+func (this *Client) PutIndexSettings(ctx context.Context, settings interface{}, index ...string) (*IndicesResponse, error) {
+	if settings == nil {
+		return nil, fmt.Errorf("settings are required")
+	}
+	path, err := indicesPath(index, "_settings")
+	if err != nil {
+		return nil, err
+	}
+	response, err := this.httpRequest(ctx, "PUT", path, nil, settings, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(IndicesResponse)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,126 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestCreateIndex(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/md_fin_waybill_202003" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		expected := `{"aliases":{"md_fin_waybill":{}},"mappings":{"properties":{"F_OrderTime":{"type":"date"}}},"settings":{"number_of_shards":3}}`
+		if string(body) != expected {
+			t.Errorf("expected body\n%s\ngot\n%s", expected, string(body))
+		}
+		w.Write([]byte(`{"acknowledged": true, "shards_acknowledged": true, "index": "md_fin_waybill_202003"}`))
+	})
+	response, err := client.CreateIndex("md_fin_waybill_202003").
+		Settings(map[string]interface{}{"number_of_shards": 3}).
+		Mappings(map[string]interface{}{"properties": map[string]interface{}{
+			"F_OrderTime": map[string]interface{}{"type": "date"},
+		}}).
+		Alias("md_fin_waybill", nil).
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !response.Acknowledged || response.Index != "md_fin_waybill_202003" {
+		t.Errorf("unexpected response %+v", response)
+	}
+}
+
+func TestIndexExists(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "HEAD" {
+			t.Errorf("unexpected method %s", r.Method)
+		}
+		if r.URL.Path != "/md_fin_waybill_202003" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	for index, expected := range map[string]bool{"md_fin_waybill_202003": true, "md_fin_waybill_209912": false} {
+		exists, err := client.IndexExists(context.Background(), index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exists != expected {
+			t.Errorf("%s: expected exists %v, got %v", index, expected, exists)
+		}
+	}
+	if _, err := client.DeleteIndex().Do(context.Background()); err == nil {
+		t.Error("expected error deleting without index")
+	}
+}
+
+func TestIndicesMaintenance(t *testing.T) {
+	requests := make([]string, 0)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath()+"?"+r.URL.RawQuery)
+		if r.URL.Path == "/md_fin_waybill_202002/_settings" && r.Method == "GET" {
+			w.Write([]byte(`{"md_fin_waybill_202002": {"settings": {"index": {"number_of_replicas": "1"}}}}`))
+			return
+		}
+		w.Write([]byte(`{"acknowledged": true, "_shards": {"total": 2, "successful": 2, "failed": 0}}`))
+	})
+	ctx := context.Background()
+	settings, err := client.GetIndexSettings(ctx, "md_fin_waybill_202002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index, ok := settings["md_fin_waybill_202002"].Settings["index"].(map[string]interface{}); !ok || index["number_of_replicas"] != "1" {
+		t.Errorf("unexpected settings %+v", settings)
+	}
+	if _, err := client.PutIndexSettings(ctx, map[string]interface{}{"index": map[string]interface{}{"number_of_replicas": 0}}, "md_fin_waybill_202002"); err != nil {
+		t.Fatal(err)
+	}
+	response, err := client.Refresh(ctx, "md_fin_waybill_202002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Shards == nil || response.Shards.Successful != 2 {
+		t.Errorf("unexpected response %+v", response)
+	}
+	if _, err := client.Flush("md_fin_waybill_202002").Force(true).Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Forcemerge("md_fin_waybill_202002").MaxNumSegments(1).Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ClearCache("md_fin_waybill_202002").Fielddata(true, "F_O_CustomerName").Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	closed, err := client.CloseIndex(ctx, "md_fin_waybill_202002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closed.Acknowledged {
+		t.Errorf("expected the close to be acknowledged, got %+v", closed)
+	}
+	if _, err := client.OpenIndex(ctx, "md_fin_waybill_202002"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"GET /md_fin_waybill_202002/_settings?",
+		"PUT /md_fin_waybill_202002/_settings?",
+		"POST /md_fin_waybill_202002/_refresh?",
+		"POST /md_fin_waybill_202002/_flush?force=true",
+		"POST /md_fin_waybill_202002/_forcemerge?max_num_segments=1",
+		"POST /md_fin_waybill_202002/_cache/clear?fielddata=true&fields=F_O_CustomerName",
+		"POST /md_fin_waybill_202002/_close?",
+		"POST /md_fin_waybill_202002/_open?",
+	}
+	if len(requests) != len(expected) {
+		t.Fatalf("expected %d requests, got %v", len(expected), requests)
+	}
+	for i := range expected {
+		if requests[i] != expected[i] {
+			t.Errorf("request %d: expected %q, got %q", i, expected[i], requests[i])
+		}
+	}
+}
@@ -0,0 +1,7 @@
+package go_elasticsearch
+
+// Logger specifies the interface for all log operations of the client.
+// *log.Logger from the standard library satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
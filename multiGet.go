@@ -0,0 +1,198 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// MultiGetService gets several documents by id in a single round trip with
+// the _mget endpoint.
+//
+//	result, err := client.MultiGet().
+//		AddIds("md_fin_waybill", "W1", "W2").
+//		Add(NewMultiGetItem("md_fin_customer", "C1").SourceIncludes("F_O_CustomerName")).
+//		Do(ctx)
+type MultiGetService struct {
+	client     *Client
+	items      []*MultiGetItem
+	preference string
+	realtime   *bool
+	refresh    bool
+}
+
+func NewMultiGetService(c *Client) *MultiGetService {
+	return &MultiGetService{
+		client: c,
+		items:  make([]*MultiGetItem, 0),
+	}
+}
+
+func (this *Client) MultiGet() *MultiGetService {
+	return NewMultiGetService(this)
+}
+
+// Add adds documents, which are returned in order.
+func (this *MultiGetService) Add(items ...*MultiGetItem) *MultiGetService {
+	this.items = append(this.items, items...)
+	return this
+}
+
+// AddIds adds the documents of an index with the given ids.
+func (this *MultiGetService) AddIds(index string, ids ...string) *MultiGetService {
+	for _, id := range ids {
+		this.items = append(this.items, NewMultiGetItem(index, id))
+	}
+	return this
+}
+
+func (this *MultiGetService) Preference(preference string) *MultiGetService {
+	this.preference = preference
+	return this
+}
+
+// Realtime set to false gets the documents from the last refreshed state of
+// the indices, see GetService.Realtime.
+func (this *MultiGetService) Realtime(realtime bool) *MultiGetService {
+	this.realtime = &realtime
+	return this
+}
+
+// Refresh refreshes the shards before getting the documents.
+func (this *MultiGetService) Refresh(refresh bool) *MultiGetService {
+	this.refresh = refresh
+	return this
+}
+
+// Body returns the body of the request, with a "docs" entry per document.
+func (this *MultiGetService) Body() (map[string]interface{}, error) {
+	docs := make([]interface{}, 0, len(this.items))
+	for i, item := range this.items {
+		source, err := item.Source()
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %v", i, err)
+		}
+		docs = append(docs, source)
+	}
+	return map[string]interface{}{"docs": docs}, nil
+}
+
+func (this *MultiGetService) Do(ctx context.Context) (*MultiGetResult, error) {
+	if len(this.items) == 0 {
+		return &MultiGetResult{Docs: make([]*GetResult, 0)}, nil
+	}
+	body, err := this.Body()
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	if this.preference != "" {
+		params.Set("preference", this.preference)
+	}
+	if this.realtime != nil {
+		params.Set("realtime", strconv.FormatBool(*this.realtime))
+	}
+	if this.refresh {
+		params.Set("refresh", "true")
+	}
+	response, err := this.client.httpRequest(ctx, "POST", "/_mget", params, body, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(MultiGetResult)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// MultiGetItem is a document of a multi get.
+type MultiGetItem struct {
+	index          string
+	typ            string
+	id             string
+	routing        string
+	source         *bool
+	sourceIncludes []string
+	sourceExcludes []string
+	storedFields   []string
+}
+
+func NewMultiGetItem(index, id string) *MultiGetItem {
+	return &MultiGetItem{
+		index: index,
+		id:    id,
+	}
+}
+
+// Type sets the type of the document, for Elasticsearch 6.x.
+func (this *MultiGetItem) Type(typ string) *MultiGetItem {
+	this.typ = typ
+	return this
+}
+
+func (this *MultiGetItem) Routing(routing string) *MultiGetItem {
+	this.routing = routing
+	return this
+}
+
+// FetchSource set to false leaves out the source of the document.
+func (this *MultiGetItem) FetchSource(fetchSource bool) *MultiGetItem {
+	this.source = &fetchSource
+	return this
+}
+
+func (this *MultiGetItem) SourceIncludes(fields ...string) *MultiGetItem {
+	this.sourceIncludes = append(this.sourceIncludes, fields...)
+	return this
+}
+
+func (this *MultiGetItem) SourceExcludes(fields ...string) *MultiGetItem {
+	this.sourceExcludes = append(this.sourceExcludes, fields...)
+	return this
+}
+
+func (this *MultiGetItem) StoredFields(fields ...string) *MultiGetItem {
+	this.storedFields = append(this.storedFields, fields...)
+	return this
+}
+
+func (this *MultiGetItem) Source() (interface{}, error) {
+	if this.index == "" {
+		return nil, fmt.Errorf("index is required")
+	}
+	if this.id == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+	source := map[string]interface{}{"_index": this.index, "_id": this.id}
+	if this.typ != "" {
+		source["_type"] = this.typ
+	}
+	if this.routing != "" {
+		source["routing"] = this.routing
+	}
+	if len(this.sourceIncludes) > 0 || len(this.sourceExcludes) > 0 {
+		filter := make(map[string]interface{})
+		if len(this.sourceIncludes) > 0 {
+			filter["includes"] = this.sourceIncludes
+		}
+		if len(this.sourceExcludes) > 0 {
+			filter["excludes"] = this.sourceExcludes
+		}
+		source["_source"] = filter
+	} else if this.source != nil {
+		source["_source"] = *this.source
+	}
+	if len(this.storedFields) > 0 {
+		source["stored_fields"] = this.storedFields
+	}
+	return source, nil
+}
+
+// MultiGetResult holds the documents of a multi get, in the order they were
+// added. Missing documents have Found false, failed ones an Error.
+type MultiGetResult struct {
+	Docs []*GetResult `json:"docs"`
+}
@@ -0,0 +1,43 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestMultiGet(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_mget" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		expected := `{"docs":[{"_id":"W1","_index":"md_fin_waybill"},{"_id":"W2","_index":"md_fin_waybill"},` +
+			`{"_id":"C1","_index":"md_fin_customer","_source":{"includes":["F_O_CustomerName"]},"routing":"c1"}]}`
+		if string(body) != expected {
+			t.Errorf("expected body\n%s\ngot\n%s", expected, string(body))
+		}
+		w.Write([]byte(`{"docs": [
+			{"_index": "md_fin_waybill", "_id": "W1", "found": true, "_source": {"F_OrderNo": "O1"}},
+			{"_index": "md_fin_waybill", "_id": "W2", "found": false},
+			{"_index": "md_fin_customer", "_id": "C1", "error": {"type": "index_not_found_exception", "reason": "no such index [md_fin_customer]"}}
+		]}`))
+	})
+	result, err := client.MultiGet().
+		AddIds("md_fin_waybill", "W1", "W2").
+		Add(NewMultiGetItem("md_fin_customer", "C1").Routing("c1").SourceIncludes("F_O_CustomerName")).
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Docs) != 3 {
+		t.Fatalf("expected 3 documents, got %d", len(result.Docs))
+	}
+	if !result.Docs[0].Found || result.Docs[1].Found {
+		t.Errorf("unexpected found flags %v, %v", result.Docs[0].Found, result.Docs[1].Found)
+	}
+	if result.Docs[2].Error == nil || result.Docs[2].Error.Type != "index_not_found_exception" {
+		t.Errorf("expected an error for the third document, got %+v", result.Docs[2])
+	}
+}
@@ -0,0 +1,172 @@
+package go_elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// multiSearchHeaderOptions are the query options that are sent in the
+// header line of a search in a multi search.
+var multiSearchHeaderOptions = map[string]bool{
+	"search_type": true, "preference": true, "routing": true, "request_cache": true,
+	"allow_no_indices": true, "expand_wildcards": true, "ignore_unavailable": true,
+}
+
+// multiSearchBodyOptions are the query options that are sent in the body of
+// a search in a multi search, with the parser of their value. The other
+// options only exist as URL parameters of a search and are rejected.
+var multiSearchBodyOptions = map[string]func(value string) (interface{}, error){
+	"terminate_after":     parseIntOption,
+	"timeout":             parseStringOption,
+	"track_scores":        parseBoolOption,
+	"min_score":           parseFloatOption,
+	"seq_no_primary_term": parseBoolOption,
+}
+
+func parseStringOption(value string) (interface{}, error) {
+	return value, nil
+}
+
+func parseIntOption(value string) (interface{}, error) {
+	return strconv.Atoi(value)
+}
+
+func parseFloatOption(value string) (interface{}, error) {
+	return strconv.ParseFloat(value, 64)
+}
+
+func parseBoolOption(value string) (interface{}, error) {
+	return strconv.ParseBool(value)
+}
+
+// MultiSearchService executes several searches in a single round trip with
+// the _msearch endpoint.
+//
+//	result, err := client.MultiSearch().Add(query1, query2).Do(ctx)
+//	first, err := result.Result(0)
+type MultiSearchService struct {
+	client                *Client
+	queries               []*Query
+	maxConcurrentSearches int
+}
+
+func NewMultiSearchService(c *Client) *MultiSearchService {
+	return &MultiSearchService{
+		client:  c,
+		queries: make([]*Query, 0),
+	}
+}
+
+func (this *Client) MultiSearch() *MultiSearchService {
+	return NewMultiSearchService(this)
+}
+
+// Add adds searches, which are executed and returned in order.
+func (this *MultiSearchService) Add(queries ...*Query) *MultiSearchService {
+	this.queries = append(this.queries, queries...)
+	return this
+}
+
+// MaxConcurrentSearches limits the number of searches executed concurrently
+// by Elasticsearch.
+func (this *MultiSearchService) MaxConcurrentSearches(max int) *MultiSearchService {
+	this.maxConcurrentSearches = max
+	return this
+}
+
+// Body returns the NDJSON body of the multi search: a header line with the
+// indices and options of each search, followed by its body built with
+// QueryBuilder.Build. As _msearch has no per-search URL, the options of a
+// search that do not belong in the header, e.g. Options("terminate_after",
+// "100"), are sent in the body if it supports them; any other option is an
+// error.
+func (this *MultiSearchService) Body() (NDJSON, error) {
+	builder := QueryBuilder{}
+	lines := make(NDJSON, 0, 2*len(this.queries))
+	for i, query := range this.queries {
+		header := make(map[string]interface{})
+		if len(query.index) > 0 {
+			header["index"] = query.index
+		}
+		if len(query.typ) > 0 {
+			header["type"] = strings.Join(query.typ, ",")
+		}
+		body, err := builder.Build(query)
+		if err != nil {
+			return nil, fmt.Errorf("search %d: %v", i, err)
+		}
+		for name, value := range query.options {
+			if multiSearchHeaderOptions[name] {
+				header[name] = value
+				continue
+			}
+			parse, ok := multiSearchBodyOptions[name]
+			if !ok {
+				return nil, fmt.Errorf("search %d: option %q is not supported in a multi search", i, name)
+			}
+			if _, ok := body[name]; ok {
+				return nil, fmt.Errorf("search %d: option %q is set twice", i, name)
+			}
+			parsed, err := parse(value)
+			if err != nil {
+				return nil, fmt.Errorf("search %d: option %q: %v", i, name, err)
+			}
+			body[name] = parsed
+		}
+		lines = append(lines, header, body)
+	}
+	return lines, nil
+}
+
+func (this *MultiSearchService) Do(ctx context.Context) (*MultiSearchResult, error) {
+	body, err := this.Body()
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	if this.maxConcurrentSearches > 0 {
+		params.Set("max_concurrent_searches", strconv.Itoa(this.maxConcurrentSearches))
+	}
+	response, err := this.client.httpRequest(ctx, "POST", "/_msearch", params, body, false)
+	if err != nil {
+		return nil, err
+	}
+	result := new(MultiSearchResult)
+	decoder := json.NewDecoder(bytes.NewReader(response.Body))
+	decoder.UseNumber()
+	if err := decoder.Decode(result); err != nil {
+		return nil, err
+	}
+	if len(result.Responses) != len(this.queries) {
+		return nil, fmt.Errorf("multi search returned %d responses for %d searches", len(result.Responses), len(this.queries))
+	}
+	return result, nil
+}
+
+// MultiSearchResult holds the results of a multi search, in the order the
+// searches were added.
+type MultiSearchResult struct {
+	Took      int64           `json:"took"`
+	Responses []*SearchResult `json:"responses"`
+}
+
+// Result returns the result of the i-th search, or its error as *Error if
+// that search failed.
+func (this *MultiSearchResult) Result(i int) (*SearchResult, error) {
+	if i < 0 || i >= len(this.Responses) {
+		return nil, fmt.Errorf("multi search has no result %d", i)
+	}
+	result := this.Responses[i]
+	if result == nil {
+		return nil, fmt.Errorf("multi search has no result %d", i)
+	}
+	if result.Error != nil {
+		return nil, &Error{Status: result.Status, Details: result.Error}
+	}
+	return result, nil
+}
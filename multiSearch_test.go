@@ -0,0 +1,83 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestMultiSearch(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_msearch" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+			t.Errorf("expected NDJSON content type, got %q", ct)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		expected := `{"index":["md_fin_waybill"]}` + "\n" +
+			`{"aggregations":{},"query":{"bool":{"must":[{"range":{"F_OrderTime":{"gte":"2020-03-07T00:00:00","lte":"2020-03-07T23:59:59"}}}]}},"size":10}` + "\n" +
+			`{"index":["md_fin_customer"],"preference":"_local"}` + "\n" +
+			`{"aggregations":{},"size":5,"terminate_after":100,"timeout":"1s"}` + "\n"
+		if string(body) != expected {
+			t.Errorf("expected body\n%s\ngot\n%s", expected, string(body))
+		}
+		w.Write([]byte(`{"took": 5, "responses": [
+			{"took": 2, "hits": {"total": {"value": 1, "relation": "eq"}, "hits": [{"_id": "1"}]}, "status": 200},
+			{"error": {"type": "index_not_found_exception", "reason": "no such index [md_fin_customer]"}, "status": 404}
+		]}`))
+	})
+
+	result, err := client.MultiSearch().Add(
+		client.Search("md_fin_waybill").AndWhere("between", "F_OrderTime", "2020-03-07T00:00:00", "2020-03-07T23:59:59"),
+		client.Search("md_fin_customer").Limit(5).Timeout("1s").Options("preference", "_local").Options("terminate_after", "100"),
+	).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := result.Result(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.TotalHits() != 1 {
+		t.Errorf("expected 1 hit, got %d", first.TotalHits())
+	}
+	if _, err := result.Result(1); err == nil {
+		t.Error("expected error for the second search")
+	} else if e, ok := err.(*Error); !ok || e.Status != 404 {
+		t.Errorf("expected *Error with status 404, got %v", err)
+	}
+}
+
+func TestMultiSearchResponseCount(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"took": 5, "responses": [{"took": 2, "hits": {"hits": []}, "status": 200}]}`))
+	})
+	_, err := client.MultiSearch().Add(client.Search("md_fin_waybill"), client.Search("md_fin_customer")).Do(context.Background())
+	if err == nil {
+		t.Error("expected error for a missing response")
+	}
+
+	result := &MultiSearchResult{Responses: []*SearchResult{nil}}
+	if _, err := result.Result(0); err == nil {
+		t.Error("expected error for a nil response")
+	}
+}
+
+func TestMultiSearchOptions(t *testing.T) {
+	client, _ := NewClient()
+	tests := []struct {
+		name  string
+		query *Query
+	}{
+		{"URL parameter", client.Search("md_fin_waybill").Options("typed_keys", "true")},
+		{"set twice", client.Search("md_fin_waybill").Timeout("1s").Options("timeout", "2s")},
+		{"not a number", client.Search("md_fin_waybill").Options("terminate_after", "many")},
+	}
+	for _, test := range tests {
+		if _, err := client.MultiSearch().Add(test.query).Body(); err == nil {
+			t.Errorf("%s: expected error", test.name)
+		}
+	}
+}
@@ -0,0 +1,553 @@
+package go_elasticsearch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Aggregation is a typed aggregation. It can be added to a query with
+// Query.Aggregation, or used as a sub-aggregation in the "aggregations"
+// options passed to Query.AddAgg and Query.AddAggregate.
+type Aggregation interface {
+	// Source returns the JSON-serializable body of the aggregation.
+	Source() (interface{}, error)
+}
+
+// PipelineAggregation is an aggregation working on the output of other
+// aggregations. Its buckets paths are validated against the aggregations
+// registered on the same query when the query is built.
+type PipelineAggregation interface {
+	Aggregation
+	BucketsPaths() []string
+}
+
+// Gap policies for pipeline aggregations.
+const (
+	GapPolicySkip        = "skip"
+	GapPolicyInsertZeros = "insert_zeros"
+	GapPolicyKeepValues  = "keep_values"
+)
+
+// -- avg_bucket, max_bucket, sum_bucket --
+
+// AvgBucketAggregation calculates the mean value of a metric in a sibling
+// multi-bucket aggregation.
+type AvgBucketAggregation struct {
+	bucketsPath string
+	format      string
+	gapPolicy   string
+}
+
+func NewAvgBucketAggregation() *AvgBucketAggregation {
+	return &AvgBucketAggregation{}
+}
+
+func (this *AvgBucketAggregation) BucketsPath(bucketsPath string) *AvgBucketAggregation {
+	this.bucketsPath = bucketsPath
+	return this
+}
+
+func (this *AvgBucketAggregation) Format(format string) *AvgBucketAggregation {
+	this.format = format
+	return this
+}
+
+func (this *AvgBucketAggregation) GapPolicy(gapPolicy string) *AvgBucketAggregation {
+	this.gapPolicy = gapPolicy
+	return this
+}
+
+func (this *AvgBucketAggregation) BucketsPaths() []string {
+	return []string{this.bucketsPath}
+}
+
+func (this *AvgBucketAggregation) Source() (interface{}, error) {
+	return pipelineSource("avg_bucket", this.bucketsPath, this.format, this.gapPolicy, nil), nil
+}
+
+// MaxBucketAggregation finds the bucket(s) with the maximum value of a metric
+// in a sibling multi-bucket aggregation.
+type MaxBucketAggregation struct {
+	bucketsPath string
+	format      string
+	gapPolicy   string
+}
+
+func NewMaxBucketAggregation() *MaxBucketAggregation {
+	return &MaxBucketAggregation{}
+}
+
+func (this *MaxBucketAggregation) BucketsPath(bucketsPath string) *MaxBucketAggregation {
+	this.bucketsPath = bucketsPath
+	return this
+}
+
+func (this *MaxBucketAggregation) Format(format string) *MaxBucketAggregation {
+	this.format = format
+	return this
+}
+
+func (this *MaxBucketAggregation) GapPolicy(gapPolicy string) *MaxBucketAggregation {
+	this.gapPolicy = gapPolicy
+	return this
+}
+
+func (this *MaxBucketAggregation) BucketsPaths() []string {
+	return []string{this.bucketsPath}
+}
+
+func (this *MaxBucketAggregation) Source() (interface{}, error) {
+	return pipelineSource("max_bucket", this.bucketsPath, this.format, this.gapPolicy, nil), nil
+}
+
+// SumBucketAggregation calculates the sum of a metric across all buckets of
+// a sibling multi-bucket aggregation.
+type SumBucketAggregation struct {
+	bucketsPath string
+	format      string
+	gapPolicy   string
+}
+
+func NewSumBucketAggregation() *SumBucketAggregation {
+	return &SumBucketAggregation{}
+}
+
+func (this *SumBucketAggregation) BucketsPath(bucketsPath string) *SumBucketAggregation {
+	this.bucketsPath = bucketsPath
+	return this
+}
+
+func (this *SumBucketAggregation) Format(format string) *SumBucketAggregation {
+	this.format = format
+	return this
+}
+
+func (this *SumBucketAggregation) GapPolicy(gapPolicy string) *SumBucketAggregation {
+	this.gapPolicy = gapPolicy
+	return this
+}
+
+func (this *SumBucketAggregation) BucketsPaths() []string {
+	return []string{this.bucketsPath}
+}
+
+func (this *SumBucketAggregation) Source() (interface{}, error) {
+	return pipelineSource("sum_bucket", this.bucketsPath, this.format, this.gapPolicy, nil), nil
+}
+
+// -- derivative, cumulative_sum, moving_fn --
+
+// DerivativeAggregation calculates the derivative of a metric in a parent
+// histogram or date_histogram aggregation.
+type DerivativeAggregation struct {
+	bucketsPath string
+	format      string
+	gapPolicy   string
+	unit        string
+}
+
+func NewDerivativeAggregation() *DerivativeAggregation {
+	return &DerivativeAggregation{}
+}
+
+func (this *DerivativeAggregation) BucketsPath(bucketsPath string) *DerivativeAggregation {
+	this.bucketsPath = bucketsPath
+	return this
+}
+
+func (this *DerivativeAggregation) Format(format string) *DerivativeAggregation {
+	this.format = format
+	return this
+}
+
+func (this *DerivativeAggregation) GapPolicy(gapPolicy string) *DerivativeAggregation {
+	this.gapPolicy = gapPolicy
+	return this
+}
+
+// Unit sets the unit of the x-axis of the derivative, e.g. "1d" or "1M".
+func (this *DerivativeAggregation) Unit(unit string) *DerivativeAggregation {
+	this.unit = unit
+	return this
+}
+
+func (this *DerivativeAggregation) BucketsPaths() []string {
+	return []string{this.bucketsPath}
+}
+
+func (this *DerivativeAggregation) Source() (interface{}, error) {
+	params := map[string]interface{}{}
+	if this.unit != "" {
+		params["unit"] = this.unit
+	}
+	return pipelineSource("derivative", this.bucketsPath, this.format, this.gapPolicy, params), nil
+}
+
+// CumulativeSumAggregation calculates the cumulative sum of a metric in a
+// parent histogram or date_histogram aggregation.
+type CumulativeSumAggregation struct {
+	bucketsPath string
+	format      string
+}
+
+func NewCumulativeSumAggregation() *CumulativeSumAggregation {
+	return &CumulativeSumAggregation{}
+}
+
+func (this *CumulativeSumAggregation) BucketsPath(bucketsPath string) *CumulativeSumAggregation {
+	this.bucketsPath = bucketsPath
+	return this
+}
+
+func (this *CumulativeSumAggregation) Format(format string) *CumulativeSumAggregation {
+	this.format = format
+	return this
+}
+
+func (this *CumulativeSumAggregation) BucketsPaths() []string {
+	return []string{this.bucketsPath}
+}
+
+func (this *CumulativeSumAggregation) Source() (interface{}, error) {
+	return pipelineSource("cumulative_sum", this.bucketsPath, this.format, "", nil), nil
+}
+
+// MovingFnAggregation runs a script over a sliding window of the buckets of
+// a parent histogram or date_histogram aggregation.
+type MovingFnAggregation struct {
+	bucketsPath string
+	format      string
+	gapPolicy   string
+	script      interface{}
+	window      int
+	shift       *int
+}
+
+func NewMovingFnAggregation() *MovingFnAggregation {
+	return &MovingFnAggregation{}
+}
+
+func (this *MovingFnAggregation) BucketsPath(bucketsPath string) *MovingFnAggregation {
+	this.bucketsPath = bucketsPath
+	return this
+}
+
+func (this *MovingFnAggregation) Format(format string) *MovingFnAggregation {
+	this.format = format
+	return this
+}
+
+func (this *MovingFnAggregation) GapPolicy(gapPolicy string) *MovingFnAggregation {
+	this.gapPolicy = gapPolicy
+	return this
+}
+
+// Script sets the script run on each window, either as a string such as
+// "MovingFunctions.unweightedAvg(values)" or as a script object.
+func (this *MovingFnAggregation) Script(script interface{}) *MovingFnAggregation {
+	this.script = script
+	return this
+}
+
+func (this *MovingFnAggregation) Window(window int) *MovingFnAggregation {
+	this.window = window
+	return this
+}
+
+func (this *MovingFnAggregation) Shift(shift int) *MovingFnAggregation {
+	this.shift = &shift
+	return this
+}
+
+func (this *MovingFnAggregation) BucketsPaths() []string {
+	return []string{this.bucketsPath}
+}
+
+func (this *MovingFnAggregation) Source() (interface{}, error) {
+	if this.script == nil {
+		return nil, fmt.Errorf("moving_fn aggregation requires a script")
+	}
+	if this.window <= 0 {
+		return nil, fmt.Errorf("moving_fn aggregation requires a positive window")
+	}
+	params := map[string]interface{}{
+		"script": this.script,
+		"window": this.window,
+	}
+	if this.shift != nil {
+		params["shift"] = *this.shift
+	}
+	return pipelineSource("moving_fn", this.bucketsPath, this.format, this.gapPolicy, params), nil
+}
+
+// -- bucket_script, bucket_selector, bucket_sort --
+
+// BucketScriptAggregation runs a script per bucket of a parent multi-bucket
+// aggregation, with the variables of the script mapped to buckets paths.
+//
+//	NewBucketScriptAggregation().
+//		BucketsPathsMap(map[string]string{"freight": "carriage", "orders": "_count"}).
+//		Script("params.freight / params.orders")
+type BucketScriptAggregation struct {
+	bucketsPaths map[string]string
+	format       string
+	gapPolicy    string
+	script       interface{}
+}
+
+func NewBucketScriptAggregation() *BucketScriptAggregation {
+	return &BucketScriptAggregation{
+		bucketsPaths: make(map[string]string),
+	}
+}
+
+// AddBucketsPath maps the script variable name to a buckets path.
+func (this *BucketScriptAggregation) AddBucketsPath(name, bucketsPath string) *BucketScriptAggregation {
+	this.bucketsPaths[name] = bucketsPath
+	return this
+}
+
+func (this *BucketScriptAggregation) BucketsPathsMap(bucketsPaths map[string]string) *BucketScriptAggregation {
+	for name, bucketsPath := range bucketsPaths {
+		this.bucketsPaths[name] = bucketsPath
+	}
+	return this
+}
+
+func (this *BucketScriptAggregation) Format(format string) *BucketScriptAggregation {
+	this.format = format
+	return this
+}
+
+func (this *BucketScriptAggregation) GapPolicy(gapPolicy string) *BucketScriptAggregation {
+	this.gapPolicy = gapPolicy
+	return this
+}
+
+// Script sets the script, either as a string or as a script object.
+func (this *BucketScriptAggregation) Script(script interface{}) *BucketScriptAggregation {
+	this.script = script
+	return this
+}
+
+func (this *BucketScriptAggregation) BucketsPaths() []string {
+	return bucketsPathValues(this.bucketsPaths)
+}
+
+func (this *BucketScriptAggregation) Source() (interface{}, error) {
+	if this.script == nil {
+		return nil, fmt.Errorf("bucket_script aggregation requires a script")
+	}
+	params := map[string]interface{}{
+		"buckets_path": this.bucketsPaths,
+		"script":       this.script,
+	}
+	return pipelineSource("bucket_script", "", this.format, this.gapPolicy, params), nil
+}
+
+// BucketSelectorAggregation keeps only the buckets of a parent multi-bucket
+// aggregation for which the script returns true.
+type BucketSelectorAggregation struct {
+	bucketsPaths map[string]string
+	gapPolicy    string
+	script       interface{}
+}
+
+func NewBucketSelectorAggregation() *BucketSelectorAggregation {
+	return &BucketSelectorAggregation{
+		bucketsPaths: make(map[string]string),
+	}
+}
+
+// AddBucketsPath maps the script variable name to a buckets path.
+func (this *BucketSelectorAggregation) AddBucketsPath(name, bucketsPath string) *BucketSelectorAggregation {
+	this.bucketsPaths[name] = bucketsPath
+	return this
+}
+
+func (this *BucketSelectorAggregation) BucketsPathsMap(bucketsPaths map[string]string) *BucketSelectorAggregation {
+	for name, bucketsPath := range bucketsPaths {
+		this.bucketsPaths[name] = bucketsPath
+	}
+	return this
+}
+
+func (this *BucketSelectorAggregation) GapPolicy(gapPolicy string) *BucketSelectorAggregation {
+	this.gapPolicy = gapPolicy
+	return this
+}
+
+// Script sets the script, either as a string or as a script object.
+func (this *BucketSelectorAggregation) Script(script interface{}) *BucketSelectorAggregation {
+	this.script = script
+	return this
+}
+
+func (this *BucketSelectorAggregation) BucketsPaths() []string {
+	return bucketsPathValues(this.bucketsPaths)
+}
+
+func (this *BucketSelectorAggregation) Source() (interface{}, error) {
+	if this.script == nil {
+		return nil, fmt.Errorf("bucket_selector aggregation requires a script")
+	}
+	params := map[string]interface{}{
+		"buckets_path": this.bucketsPaths,
+		"script":       this.script,
+	}
+	return pipelineSource("bucket_selector", "", "", this.gapPolicy, params), nil
+}
+
+// BucketSortAggregation sorts and truncates the buckets of a parent
+// multi-bucket aggregation.
+type BucketSortAggregation struct {
+	sort      []map[string]string
+	from      int
+	size      int
+	gapPolicy string
+}
+
+func NewBucketSortAggregation() *BucketSortAggregation {
+	return &BucketSortAggregation{
+		sort: make([]map[string]string, 0),
+	}
+}
+
+// Sort adds a sort on the given buckets path, e.g. "carriage" or "_count".
+func (this *BucketSortAggregation) Sort(bucketsPath string, ascending bool) *BucketSortAggregation {
+	order := "desc"
+	if ascending {
+		order = "asc"
+	}
+	this.sort = append(this.sort, map[string]string{bucketsPath: order})
+	return this
+}
+
+func (this *BucketSortAggregation) From(from int) *BucketSortAggregation {
+	this.from = from
+	return this
+}
+
+func (this *BucketSortAggregation) Size(size int) *BucketSortAggregation {
+	this.size = size
+	return this
+}
+
+func (this *BucketSortAggregation) GapPolicy(gapPolicy string) *BucketSortAggregation {
+	this.gapPolicy = gapPolicy
+	return this
+}
+
+func (this *BucketSortAggregation) BucketsPaths() []string {
+	paths := make([]string, 0, len(this.sort))
+	for _, sort := range this.sort {
+		for bucketsPath := range sort {
+			paths = append(paths, bucketsPath)
+		}
+	}
+	return paths
+}
+
+func (this *BucketSortAggregation) Source() (interface{}, error) {
+	params := map[string]interface{}{}
+	if len(this.sort) > 0 {
+		sort := make([]interface{}, 0, len(this.sort))
+		for _, s := range this.sort {
+			for bucketsPath, order := range s {
+				sort = append(sort, map[string]interface{}{bucketsPath: map[string]string{"order": order}})
+			}
+		}
+		params["sort"] = sort
+	}
+	if this.from > 0 {
+		params["from"] = this.from
+	}
+	if this.size > 0 {
+		params["size"] = this.size
+	}
+	return pipelineSource("bucket_sort", "", "", this.gapPolicy, params), nil
+}
+
+func pipelineSource(typ, bucketsPath, format, gapPolicy string, params map[string]interface{}) map[string]interface{} {
+	options := make(map[string]interface{})
+	for k, v := range params {
+		options[k] = v
+	}
+	if bucketsPath != "" {
+		options["buckets_path"] = bucketsPath
+	}
+	if format != "" {
+		options["format"] = format
+	}
+	if gapPolicy != "" {
+		options["gap_policy"] = gapPolicy
+	}
+	return map[string]interface{}{typ: options}
+}
+
+func bucketsPathValues(bucketsPaths map[string]string) []string {
+	names := make([]string, 0, len(bucketsPaths))
+	for name := range bucketsPaths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		paths = append(paths, bucketsPaths[name])
+	}
+	return paths
+}
+
+// validateBucketsPath checks that every aggregation named in the buckets path
+// exists, starting from the given sibling aggregations, e.g.
+// "sales_per_month>sales", "the_stats.avg", "sale_type['hat']>sales" or "_count".
+func validateBucketsPath(bucketsPath string, siblings map[string]interface{}) error {
+	if bucketsPath == "" {
+		return fmt.Errorf("buckets_path must not be empty")
+	}
+	level := siblings
+	segments := strings.Split(bucketsPath, ">")
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		name := segment
+		if pos := strings.Index(name, "["); pos >= 0 {
+			name = name[:pos]
+		}
+		if last && (name == "_count" || name == "_key" || name == "_bucket_count") {
+			return nil
+		}
+		agg, ok := level[name]
+		if !ok && last {
+			// a trailing ".<metric>" selects a value of a multi-value metric
+			if pos := strings.Index(name, "."); pos >= 0 {
+				agg, ok = level[name[:pos]]
+			}
+		}
+		if !ok {
+			return fmt.Errorf("buckets_path %q: aggregation %q not found", bucketsPath, name)
+		}
+		if !last {
+			level = subAggregations(agg)
+			if level == nil {
+				return fmt.Errorf("buckets_path %q: aggregation %q has no sub-aggregations", bucketsPath, name)
+			}
+		}
+	}
+	return nil
+}
+
+// subAggregations returns the sub-aggregations of an aggregation passed as
+// options to Query.AddAggregate, or nil if there are none.
+func subAggregations(agg interface{}) map[string]interface{} {
+	options, ok := agg.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	for _, key := range []string{"aggregations", "aggs"} {
+		if sub, ok := options[key].(map[string]interface{}); ok {
+			return sub
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package go_elasticsearch
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPipelineAggregations(t *testing.T) {
+	client, _ := NewClient()
+	query := client.Search("md_fin_waybill").
+		AddAggregate("per_month", map[string]interface{}{
+			"date_histogram": map[string]interface{}{"field": "F_OrderTime", "calendar_interval": "month"},
+			"aggregations": map[string]interface{}{
+				"carriage":       map[string]interface{}{"sum": map[string]string{"field": "F_Freight"}},
+				"carriage_delta": NewDerivativeAggregation().BucketsPath("carriage"),
+				"per_order": NewBucketScriptAggregation().
+					AddBucketsPath("freight", "carriage").
+					AddBucketsPath("orders", "_count").
+					Script("params.freight / params.orders"),
+			},
+		}).
+		Aggregation("best_month", NewMaxBucketAggregation().BucketsPath("per_month>carriage"))
+
+	builder := QueryBuilder{}
+	body, err := builder.Build(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(body["aggregations"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"best_month":{"max_bucket":{"buckets_path":"per_month\u003ecarriage"}},` +
+		`"per_month":{"aggregations":{"carriage":{"sum":{"field":"F_Freight"}},` +
+		`"carriage_delta":{"derivative":{"buckets_path":"carriage"}},` +
+		`"per_order":{"bucket_script":{"buckets_path":{"freight":"carriage","orders":"_count"},"script":"params.freight / params.orders"}}},` +
+		`"date_histogram":{"calendar_interval":"month","field":"F_OrderTime"}}}`
+	if string(data) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, string(data))
+	}
+}
+
+func TestPipelineAggregationsInvalidBucketsPath(t *testing.T) {
+	client, _ := NewClient()
+	tests := []*Query{
+		client.Search().
+			AddAgg("per_month", "date_histogram", map[string]interface{}{"field": "F_OrderTime"}).
+			Aggregation("best_month", NewMaxBucketAggregation().BucketsPath("per_month>carriage")),
+		client.Search().
+			Aggregation("total", NewSumBucketAggregation().BucketsPath("per_day>carriage")),
+		client.Search().
+			AddAggregate("per_month", map[string]interface{}{
+				"date_histogram": map[string]interface{}{"field": "F_OrderTime", "calendar_interval": "month"},
+				"aggregations": map[string]interface{}{
+					"cumulative": NewCumulativeSumAggregation().BucketsPath("carriage"),
+				},
+			}),
+	}
+	builder := QueryBuilder{}
+	for i, query := range tests {
+		if _, err := builder.Build(query); err == nil {
+			t.Errorf("expected buckets_path error in test %d", i)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/wh5231/go-elasticsearch/uritemplates"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// OpenPointInTime opens a point in time on the given indices and returns its
+// id. The point in time is kept alive for keepAlive, e.g. "1m", after each
+// search using it.
+func (this *Client) OpenPointInTime(ctx context.Context, keepAlive string, index ...string) (string, error) {
+	if len(index) == 0 {
+		return "", fmt.Errorf("index is required")
+	}
+	path, err := uritemplates.Expand("/{index}/_pit", map[string]string{
+		"index": strings.Join(index, ","),
+	})
+	if err != nil {
+		return "", err
+	}
+	params := url.Values{}
+	params.Set("keep_alive", keepAlive)
+	response, err := this.httpRequest(ctx, "POST", path, params, nil, false)
+	if err != nil {
+		return "", err
+	}
+	ret := struct {
+		Id string `json:"id"`
+	}{}
+	if err := json.Unmarshal(response.Body, &ret); err != nil {
+		return "", err
+	}
+	return ret.Id, nil
+}
+
+// ClosePointInTime closes a point in time opened with OpenPointInTime. A
+// point in time that has expired already is not an error.
+func (this *Client) ClosePointInTime(ctx context.Context, id string) error {
+	_, err := this.httpRequest(ctx, "DELETE", "/_pit", nil, map[string]string{"id": id}, false)
+	if e, ok := err.(*Error); ok && e.Status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,114 @@
+package go_elasticsearch
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SearchExplanation explains how the score of a hit was computed, see
+// Query.Explain.
+type SearchExplanation struct {
+	Value       float64             `json:"value"`
+	Description string              `json:"description"`
+	Details     []SearchExplanation `json:"details,omitempty"`
+}
+
+// String returns the explanation as an indented tree.
+func (this *SearchExplanation) String() string {
+	var b strings.Builder
+	this.write(&b, 0)
+	return b.String()
+}
+
+func (this *SearchExplanation) write(b *strings.Builder, depth int) {
+	fmt.Fprintf(b, "%s%g %s\n", strings.Repeat("  ", depth), this.Value, this.Description)
+	for i := range this.Details {
+		this.Details[i].write(b, depth+1)
+	}
+}
+
+// SearchProfile holds the timings of a search per shard, see Query.Profile.
+type SearchProfile struct {
+	Shards []SearchProfileShardResult `json:"shards"`
+}
+
+// SearchProfileShardResult holds the timings of a search on a shard. The
+// id is formatted as "[node][index][shard]".
+type SearchProfileShardResult struct {
+	ID           string                    `json:"id"`
+	Searches     []QueryProfileShardResult `json:"searches"`
+	Aggregations []ProfileResult           `json:"aggregations"`
+}
+
+// QueryProfileShardResult holds the timings of the query and the collectors.
+type QueryProfileShardResult struct {
+	Query       []ProfileResult   `json:"query"`
+	RewriteTime int64             `json:"rewrite_time"` // in nanoseconds
+	Collector   []CollectorResult `json:"collector"`
+}
+
+// ProfileResult holds the timings of a query or aggregation, broken down by
+// low-level operation, and of its children.
+type ProfileResult struct {
+	Type        string           `json:"type"`
+	Description string           `json:"description"`
+	TimeNanos   int64            `json:"time_in_nanos"`
+	Breakdown   map[string]int64 `json:"breakdown,omitempty"`
+	Children    []ProfileResult  `json:"children,omitempty"`
+}
+
+// CollectorResult holds the timings of a collector.
+type CollectorResult struct {
+	Name      string            `json:"name"`
+	Reason    string            `json:"reason"`
+	TimeNanos int64             `json:"time_in_nanos"`
+	Children  []CollectorResult `json:"children,omitempty"`
+}
+
+// String returns the profile as an indented tree per shard, e.g.
+//
+//	Shard [node][md_fin_waybill][0]
+//	  Query (rewrite 12.3µs)
+//	    BooleanQuery 1.2ms: +F_OrderTime:[1583539200000 TO 1583625599999]
+//	  Collector
+//	    SimpleTopScoreDocCollector 450µs: search_top_hits
+func (this *SearchProfile) String() string {
+	var b strings.Builder
+	for _, shard := range this.Shards {
+		fmt.Fprintf(&b, "Shard %s\n", shard.ID)
+		for _, search := range shard.Searches {
+			fmt.Fprintf(&b, "  Query (rewrite %s)\n", time.Duration(search.RewriteTime))
+			for i := range search.Query {
+				search.Query[i].write(&b, 2)
+			}
+			if len(search.Collector) > 0 {
+				b.WriteString("  Collector\n")
+				for i := range search.Collector {
+					search.Collector[i].write(&b, 2)
+				}
+			}
+		}
+		if len(shard.Aggregations) > 0 {
+			b.WriteString("  Aggregations\n")
+			for i := range shard.Aggregations {
+				shard.Aggregations[i].write(&b, 2)
+			}
+		}
+	}
+	return b.String()
+}
+
+func (this *ProfileResult) write(b *strings.Builder, depth int) {
+	fmt.Fprintf(b, "%s%s %s: %s\n", strings.Repeat("  ", depth), this.Type, time.Duration(this.TimeNanos), this.Description)
+	for i := range this.Children {
+		this.Children[i].write(b, depth+1)
+	}
+}
+
+func (this *CollectorResult) write(b *strings.Builder, depth int) {
+	fmt.Fprintf(b, "%s%s %s: %s\n", strings.Repeat("  ", depth), this.Name, time.Duration(this.TimeNanos), this.Reason)
+	for i := range this.Children {
+		this.Children[i].write(b, depth+1)
+	}
+}
@@ -0,0 +1,54 @@
+package go_elasticsearch
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSearchResultExplanation(t *testing.T) {
+	body := `{"hits": {"hits": [{"_id": "1", "_index": "md_fin_waybill", "_shard": "[md_fin_waybill][0]", "_node": "n1", "_score": 1.2,
+		"_explanation": {"value": 1.2, "description": "weight(F_Status:open)", "details": [{"value": 2.2, "description": "boost"}]}}]}}`
+	result := new(SearchResult)
+	if err := json.Unmarshal([]byte(body), result); err != nil {
+		t.Fatal(err)
+	}
+	hit := result.Hits.Hits[0]
+	if hit.Explanation == nil || hit.Shard != "[md_fin_waybill][0]" || hit.Node != "n1" {
+		t.Fatalf("expected explanation, shard and node, got %+v", hit)
+	}
+	expected := "1.2 weight(F_Status:open)\n  2.2 boost\n"
+	if got := hit.Explanation.String(); got != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, got)
+	}
+}
+
+func TestSearchResultProfile(t *testing.T) {
+	body := `{"hits": {"hits": []}, "profile": {"shards": [{"id": "[n1][md_fin_waybill][0]",
+		"searches": [{"rewrite_time": 1500,
+			"query": [{"type": "BooleanQuery", "description": "+F_Status:open", "time_in_nanos": 2000000, "breakdown": {"score": 100},
+				"children": [{"type": "TermQuery", "description": "F_Status:open", "time_in_nanos": 900000}]}],
+			"collector": [{"name": "SimpleTopScoreDocCollector", "reason": "search_top_hits", "time_in_nanos": 450000}]}],
+		"aggregations": [{"type": "LongTermsAggregator", "description": "status", "time_in_nanos": 30000}]}]}}`
+	result := new(SearchResult)
+	if err := json.Unmarshal([]byte(body), result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Profile == nil || len(result.Profile.Shards) != 1 {
+		t.Fatalf("expected 1 profiled shard, got %+v", result.Profile)
+	}
+	query := result.Profile.Shards[0].Searches[0].Query[0]
+	if query.TimeNanos != 2000000 || query.Breakdown["score"] != 100 || len(query.Children) != 1 {
+		t.Errorf("unexpected query profile %+v", query)
+	}
+	expected := "Shard [n1][md_fin_waybill][0]\n" +
+		"  Query (rewrite 1.5µs)\n" +
+		"    BooleanQuery 2ms: +F_Status:open\n" +
+		"      TermQuery 900µs: F_Status:open\n" +
+		"  Collector\n" +
+		"    SimpleTopScoreDocCollector 450µs: search_top_hits\n" +
+		"  Aggregations\n" +
+		"    LongTermsAggregator 30µs: status\n"
+	if got := result.Profile.String(); got != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, got)
+	}
+}
@@ -2,7 +2,6 @@ package go_elasticsearch
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/wh5231/go-elasticsearch/uritemplates"
 	"net/url"
@@ -11,7 +10,7 @@ import (
 
 type Query struct {
 	client       *Client
-	scriptFields []string
+	scriptFields map[string]interface{}
 	index        []string
 	typ          []string
 	timeout      string
@@ -26,6 +25,32 @@ type Query struct {
 	//array options to be appended to the query URL, such as "search_type" for search or "timeout" for delete
 	options map[string]string
 	explain bool
+	version bool
+	profile bool
+	// trackTotalHits is either a bool or the number of hits to count accurately
+	trackTotalHits interface{}
+	searchAfter    []interface{}
+	pointInTime    *PointInTime
+	slice          map[string]int
+	highlight      *Highlight
+	storedFields   []string
+	docvalueFields []interface{}
+	functionScore  *FunctionScoreQuery
+	collapse       *Collapse
+	rescore        []*Rescore
+}
+
+// SourceFilter selects the parts of the source returned with each hit, see
+// Query.Source.
+type SourceFilter struct {
+	Includes []string `json:"includes,omitempty"`
+	Excludes []string `json:"excludes,omitempty"`
+}
+
+// PointInTime is a point in time opened with Client.OpenPointInTime.
+type PointInTime struct {
+	Id        string `json:"id"`
+	KeepAlive string `json:"keep_alive,omitempty"`
 }
 
 func NewQuery(c *Client) *Query {
@@ -50,6 +75,9 @@ func (this *Query) Type(typ ...string) *Query {
 	return this
 }
 
+// Limit sets the number of hits to return. Limit(0) returns no hits at all,
+// e.g. when only aggregations are needed; a negative limit leaves the size to
+// Elasticsearch.
 func (this *Query) Limit(i int) *Query {
 	this.limit = i
 	return this
@@ -60,11 +88,72 @@ func (this *Query) Offset(i int) *Query {
 	return this
 }
 
+// Source sets the _source option of the search: false to not return the
+// source of the hits, a list of fields or wildcard patterns to return, or a
+// *SourceFilter with includes and excludes.
 func (this *Query) Source(source interface{}) *Query {
 	this.source = source
 	return this
 }
 
+// SourceIncludes returns only the given fields of the source of the hits.
+// Wildcard patterns like "F_O_*" are supported.
+func (this *Query) SourceIncludes(fields ...string) *Query {
+	filter := this.sourceFilter()
+	filter.Includes = append(filter.Includes, fields...)
+	return this
+}
+
+// SourceExcludes leaves the given fields out of the source of the hits.
+func (this *Query) SourceExcludes(fields ...string) *Query {
+	filter := this.sourceFilter()
+	filter.Excludes = append(filter.Excludes, fields...)
+	return this
+}
+
+func (this *Query) sourceFilter() *SourceFilter {
+	filter, ok := this.source.(*SourceFilter)
+	if !ok {
+		filter = &SourceFilter{}
+		this.source = filter
+	}
+	return filter
+}
+
+// StoredFields returns the given stored fields in SearchHit.Fields. Use
+// "_none_" to return neither stored fields nor the source.
+func (this *Query) StoredFields(fields ...string) *Query {
+	this.storedFields = append(this.storedFields, fields...)
+	return this
+}
+
+// DocvalueFields returns the doc values of the given fields in
+// SearchHit.Fields.
+func (this *Query) DocvalueFields(fields ...string) *Query {
+	for _, field := range fields {
+		this.docvalueFields = append(this.docvalueFields, field)
+	}
+	return this
+}
+
+// DocvalueField returns the doc values of the field in SearchHit.Fields,
+// formatted with format, e.g. "yyyy-MM-dd" for dates.
+func (this *Query) DocvalueField(field, format string) *Query {
+	this.docvalueFields = append(this.docvalueFields, map[string]string{"field": field, "format": format})
+	return this
+}
+
+// ScriptField returns the result of the script, evaluated per hit, as the
+// field name in SearchHit.Fields. The script is either a string or a script
+// object like map[string]interface{}{"source": ..., "params": ...}.
+func (this *Query) ScriptField(name string, script interface{}) *Query {
+	if this.scriptFields == nil {
+		this.scriptFields = make(map[string]interface{})
+	}
+	this.scriptFields[name] = map[string]interface{}{"script": script}
+	return this
+}
+
 func (this *Query) AndWhere(condition ...interface{}) *Query {
 	if this.where == nil {
 		this.where = []interface{}{"and", condition}
@@ -129,6 +218,14 @@ func (this *Query) AddAggregate(name string, options map[string]interface{}) *Qu
 	return this
 }
 
+// Aggregation adds a typed aggregation, e.g. a pipeline aggregation, to this query.
+func (this *Query) Aggregation(name string, aggregation Aggregation) *Query {
+	this.aggregations[name] = aggregation
+	return this
+}
+
+// Timeout bounds the time the search may take, e.g. "1s". The hits found
+// until then are returned, with TimedOut set on the result.
 func (this *Query) Timeout(timeout string) *Query {
 	this.timeout = timeout
 	return this
@@ -138,37 +235,133 @@ func (this *Query) Options(name, options string) *Query {
 	this.options[name] = options
 	return this
 }
+// Explain asks Elasticsearch to explain the score of each hit, returned in
+// SearchHit.Explanation.
 func (this *Query) Explain(explain bool) *Query {
 	this.explain = explain
 	return this
 }
 
+// Profile asks Elasticsearch for detailed timings of the search, returned
+// in SearchResult.Profile.
+func (this *Query) Profile(profile bool) *Query {
+	this.profile = profile
+	return this
+}
+
+// Version asks Elasticsearch to return the version of each hit.
+func (this *Query) Version(version bool) *Query {
+	this.version = version
+	return this
+}
+
+// TrackTotalHits controls how accurately the total number of hits is counted.
+// Pass true to always count all hits, false to skip counting, or an int to
+// count accurately up to that number (Elasticsearch 7 defaults to 10000).
+func (this *Query) TrackTotalHits(trackTotalHits interface{}) *Query {
+	this.trackTotalHits = trackTotalHits
+	return this
+}
+
+// SearchAfter returns the hits following the hit with the given sort values.
+func (this *Query) SearchAfter(sortValues ...interface{}) *Query {
+	this.searchAfter = sortValues
+	return this
+}
+
+// PointInTime searches the given point in time instead of the indices. See
+// SearchAfterPaginator to have the point in time managed for you.
+func (this *Query) PointInTime(id, keepAlive string) *Query {
+	this.pointInTime = &PointInTime{Id: id, KeepAlive: keepAlive}
+	return this
+}
+
+// Slice restricts the query to the slice with the given id out of max
+// slices, to consume a scroll or point in time in parallel.
+func (this *Query) Slice(id, max int) *Query {
+	this.slice = map[string]int{"id": id, "max": max}
+	return this
+}
+
+// FunctionScore modifies the score of the hits matching the where
+// conditions with the functions of the function_score query. If the
+// function_score query has a query of its own, that one is used instead.
+func (this *Query) FunctionScore(functionScore *FunctionScoreQuery) *Query {
+	this.functionScore = functionScore
+	return this
+}
+
+// Collapse collapses the hits on the values of a field, returning only the
+// top hit per value.
+func (this *Query) Collapse(collapse *Collapse) *Query {
+	this.collapse = collapse
+	return this
+}
+
+// Rescore adds rescorers, applied in order to the top hits of the query.
+func (this *Query) Rescore(rescore ...*Rescore) *Query {
+	this.rescore = append(this.rescore, rescore...)
+	return this
+}
+
+// Suggest adds a suggester, whose suggestions are returned under the same
+// name in SearchResult.Suggest.
+func (this *Query) Suggest(name string, suggester Suggester) *Query {
+	this.suggest[name] = suggester
+	return this
+}
+
+// Highlight highlights the matching parts of the hits, returned in
+// SearchHit.Highlight.
+func (this *Query) Highlight(highlight *Highlight) *Query {
+	this.highlight = highlight
+	return this
+}
+
+// clone returns a copy of the query that can be modified without touching
+// the sort and paging options of the original.
+func (this *Query) clone() *Query {
+	query := *this
+	query.orderBy = append(make([]map[string]string, 0, len(this.orderBy)), this.orderBy...)
+	query.searchAfter = append([]interface{}(nil), this.searchAfter...)
+	return &query
+}
+
 //buildURL builds the URL for the operation.
 func (this *Query) BuildUrl() (string, url.Values, error) {
+	return this.buildUrl("_search")
+}
+
+// buildUrl builds the URL of the given endpoint, e.g. "_search" or "_count",
+// on the indices and types of the query.
+func (this *Query) buildUrl(endpoint string) (string, url.Values, error) {
 	var (
 		err    error
 		path   string
 		params = url.Values{}
 	)
-	if len(this.typ) > 0 && len(this.index) > 0 {
-		path, err = uritemplates.Expand("/{index}/{type}/_search", map[string]string{
-			"index": strings.Join(this.index, ","),
-			"type":  strings.Join(this.typ, ","),
-		})
-	} else if len(this.index) > 0 {
-		path, err = uritemplates.Expand("/{index}/_search", map[string]string{
-			"index": strings.Join(this.index, ","),
-		})
-	} else if len(this.typ) > 0 {
-		path, err = uritemplates.Expand("/{type}/_search", map[string]string{
-			"type": strings.Join(this.typ, ","),
-		})
-	} else {
-		path = "/_search"
-	}
-	if err != nil {
-		return "", url.Values{}, err
+	// the indices are part of the point in time, if any
+	if this.pointInTime == nil {
+		if len(this.typ) > 0 && len(this.index) > 0 {
+			path, err = uritemplates.Expand("/{index}/{type}", map[string]string{
+				"index": strings.Join(this.index, ","),
+				"type":  strings.Join(this.typ, ","),
+			})
+		} else if len(this.index) > 0 {
+			path, err = uritemplates.Expand("/{index}", map[string]string{
+				"index": strings.Join(this.index, ","),
+			})
+		} else if len(this.typ) > 0 {
+			path, err = uritemplates.Expand("/{type}", map[string]string{
+				"type": strings.Join(this.typ, ","),
+			})
+		}
+		if err != nil {
+			return "", url.Values{}, err
+		}
 	}
+	// the endpoint is not escaped, it may hold slashes like "_validate/query"
+	path += "/" + endpoint
 	// Add query string parameters
 
 	for k, v := range this.options {
@@ -177,7 +370,23 @@ func (this *Query) BuildUrl() (string, url.Values, error) {
 	return path, params, nil
 }
 
-func (this *Query) Do(ctx context.Context) (interface{}, error) {
+// buildIndexUrl is like buildUrl for endpoints that take no point in time,
+// e.g. "_count": the indices of the query are used even if a point in time
+// is set, so a query on a point in time must name its indices.
+func (this *Query) buildIndexUrl(endpoint string) (string, url.Values, error) {
+	if this.pointInTime == nil {
+		return this.buildUrl(endpoint)
+	}
+	if len(this.index) == 0 {
+		return "", url.Values{}, fmt.Errorf("%s needs the indices of the point in time", endpoint)
+	}
+	query := *this
+	query.pointInTime = nil
+	return query.buildUrl(endpoint)
+}
+
+// Do executes the search. A non-2xx response is returned as *Error.
+func (this *Query) Do(ctx context.Context) (*SearchResult, error) {
 	builder := QueryBuilder{}
 	path, values, err := this.BuildUrl()
 	if err != nil {
@@ -191,30 +400,5 @@ func (this *Query) Do(ctx context.Context) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	fmt.Println(string(response.Body))
-	result := new(SearchResult)
-	json.Unmarshal([]byte(response.Body), &result)
-	return result, err
-}
-
-type SearchResult struct {
-	Shards struct {
-		Failed     int64 `json:"failed"`
-		Successful int64 `json:"successful"`
-		Total      int64 `json:"total"`
-	} `json:"_shards"`
-	Hits struct {
-		Hits []struct {
-			ID     string           `json:"_id"`
-			Index  string           `json:"_index"`
-			Score  int64            `json:"_score"`
-			Source *json.RawMessage `json:"_source"`
-			Type   string           `json:"_type"`
-		} `json:"hits"`
-		MaxScore int64 `json:"max_score"`
-		Total    int64 `json:"total"`
-	} `json:"hits"`
-	TimedOut     bool             `json:"timed_out"`
-	Aggregations *json.RawMessage `json:"aggregations,omitempty"` // results from aggregations
-	Took         int64            `json:"took"`
+	return newSearchResult(response)
 }
@@ -14,32 +14,185 @@ type QueryBuilder struct{}
 func (this *QueryBuilder)Build(query *Query) (map[string]interface{}, error) {
 	parts := make(map[string]interface{})
 
-	if query.limit > 0{
+	if query.limit >= 0 {
 		parts["size"] = query.limit
 	}
 	if query.offset > 0{
 		parts["from"] = query.offset
 	}
+	if query.timeout != "" {
+		parts["timeout"] = query.timeout
+	}
 	if query.explain == true{
 		parts["explain"] = query.explain
 	}
-	whereQuery ,_ := this.BuildCondition(query.where)
-	if whereQuery != nil{
+	if query.profile {
+		parts["profile"] = true
+	}
+	if query.version {
+		parts["version"] = true
+	}
+	if query.trackTotalHits != nil {
+		parts["track_total_hits"] = query.trackTotalHits
+	}
+	if len(query.searchAfter) > 0 {
+		parts["search_after"] = query.searchAfter
+	}
+	if query.pointInTime != nil {
+		parts["pit"] = query.pointInTime
+	}
+	if query.slice != nil {
+		parts["slice"] = query.slice
+	}
+	if query.source != nil {
+		parts["_source"] = query.source
+	}
+	if len(query.storedFields) > 0 {
+		parts["stored_fields"] = query.storedFields
+	}
+	if len(query.docvalueFields) > 0 {
+		parts["docvalue_fields"] = query.docvalueFields
+	}
+	if len(query.scriptFields) > 0 {
+		parts["script_fields"] = query.scriptFields
+	}
+	whereQuery, err := this.BuildQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if whereQuery != nil {
 		parts["query"] = whereQuery
-	}else if query.query != nil{
-		parts["query"] = query.query
 	}
 
-	if query.orderBy != nil{
+	if len(query.orderBy) > 0 {
 		parts["sort"] = query.orderBy
 	}
 
+	if query.collapse != nil {
+		collapse, err := query.collapse.Source()
+		if err != nil {
+			return nil, err
+		}
+		parts["collapse"] = collapse
+	}
+	if len(query.rescore) > 0 {
+		rescore := make([]interface{}, 0, len(query.rescore))
+		for _, r := range query.rescore {
+			source, err := r.Source()
+			if err != nil {
+				return nil, err
+			}
+			rescore = append(rescore, source)
+		}
+		parts["rescore"] = rescore
+	}
+	if len(query.suggest) > 0 {
+		suggest := make(map[string]interface{}, len(query.suggest))
+		for name, suggester := range query.suggest {
+			if s, ok := suggester.(Suggester); ok {
+				source, err := s.Source()
+				if err != nil {
+					return nil, fmt.Errorf("suggester %q: %v", name, err)
+				}
+				suggester = source
+			}
+			suggest[name] = suggester
+		}
+		parts["suggest"] = suggest
+	}
+	if query.highlight != nil {
+		highlight, err := query.highlight.Source()
+		if err != nil {
+			return nil, err
+		}
+		parts["highlight"] = highlight
+	}
+
 	if query.aggregations != nil{
-		parts["aggregations"] = query.aggregations
+		aggregations, err := this.BuildAggregations(query.aggregations)
+		if err != nil {
+			return nil, err
+		}
+		parts["aggregations"] = aggregations
 	}
 	return parts,nil
 }
 
+// BuildQuery builds the "query" part of the body from the where conditions,
+// or from the query set with Query.Query if there are none.
+func (this *QueryBuilder) BuildQuery(query *Query) (interface{}, error) {
+	whereQuery, err := this.BuildCondition(query.where)
+	if err != nil {
+		return nil, err
+	}
+	var ret interface{}
+	if whereQuery != nil {
+		ret = whereQuery
+	} else if len(query.query) == 1 {
+		// a single query passed to Query.Query is the query itself
+		ret = query.query[0]
+	} else if query.query != nil {
+		ret = query.query
+	}
+	if query.functionScore != nil {
+		return query.functionScore.source(ret)
+	}
+	return ret, nil
+}
+
+// buildQueryValue builds a query passed as interface{}: the where conditions
+// of a *Query, a typed query such as *FunctionScoreQuery, or a raw query
+// like map[string]interface{}{"match": ...} that is used as is.
+func buildQueryValue(query interface{}) (interface{}, error) {
+	switch t := query.(type) {
+	case *Query:
+		builder := QueryBuilder{}
+		return builder.BuildQuery(t)
+	case interface{ Source() (interface{}, error) }:
+		return t.Source()
+	}
+	return query, nil
+}
+
+// BuildAggregations serializes the typed aggregations found at any level and
+// validates the buckets paths of pipeline aggregations against their siblings.
+func (this *QueryBuilder) BuildAggregations(aggregations map[string]interface{}) (map[string]interface{}, error) {
+	ret := make(map[string]interface{}, len(aggregations))
+	for name, agg := range aggregations {
+		if pipeline, ok := agg.(PipelineAggregation); ok {
+			for _, bucketsPath := range pipeline.BucketsPaths() {
+				if err := validateBucketsPath(bucketsPath, aggregations); err != nil {
+					return nil, fmt.Errorf("aggregation %q: %v", name, err)
+				}
+			}
+		}
+		switch t := agg.(type) {
+		case Aggregation:
+			source, err := t.Source()
+			if err != nil {
+				return nil, fmt.Errorf("aggregation %q: %v", name, err)
+			}
+			ret[name] = source
+		case map[string]interface{}:
+			options := make(map[string]interface{}, len(t))
+			for key, value := range t {
+				if sub, ok := value.(map[string]interface{}); ok && (key == "aggregations" || key == "aggs") {
+					built, err := this.BuildAggregations(sub)
+					if err != nil {
+						return nil, err
+					}
+					value = built
+				}
+				options[key] = value
+			}
+			ret[name] = options
+		default:
+			ret[name] = agg
+		}
+	}
+	return ret, nil
+}
+
 func (this *QueryBuilder) BuildCondition(condition []interface{}) (interface{}, error) {
 	builders := map[string]ConditionFunc{
 		"not":         buildNotCondition,
@@ -0,0 +1,47 @@
+package go_elasticsearch
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestQueryBuilderSourceFiltering(t *testing.T) {
+	client, _ := NewClient()
+	tests := []struct {
+		query    *Query
+		expected string
+	}{
+		// #0: no source
+		{
+			client.Search().Limit(-1).Source(false),
+			`{"_source":false,"aggregations":{}}`,
+		},
+		// #1: includes and excludes
+		{
+			client.Search().Limit(-1).SourceIncludes("F_O_*", "F_Freight").SourceExcludes("F_O_Remark"),
+			`{"_source":{"includes":["F_O_*","F_Freight"],"excludes":["F_O_Remark"]},"aggregations":{}}`,
+		},
+		// #2: stored, docvalue and script fields
+		{
+			client.Search().Limit(-1).StoredFields("_none_").
+				DocvalueFields("F_Freight").DocvalueField("F_OrderTime", "yyyy-MM-dd").
+				ScriptField("freight_cents", "doc['F_Freight'].value * 100"),
+			`{"aggregations":{},"docvalue_fields":["F_Freight",{"field":"F_OrderTime","format":"yyyy-MM-dd"}],` +
+				`"script_fields":{"freight_cents":{"script":"doc['F_Freight'].value * 100"}},"stored_fields":["_none_"]}`,
+		},
+	}
+	builder := QueryBuilder{}
+	for i, test := range tests {
+		body, err := builder.Build(test.query)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if string(data) != test.expected {
+			t.Errorf("test %d: expected\n%s\ngot\n%s", i, test.expected, string(data))
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// ReindexService copies documents from one index to another with the
+// _reindex endpoint, e.g. after a mapping change.
+//
+//	response, err := client.Reindex().
+//		Source(client.Search("md_fin_waybill").AndWhere(">=", "F_OrderTime", "2020-01-01T00:00:00")).
+//		Dest("md_fin_waybill_v2").
+//		Slices("auto").
+//		WaitForCompletion(false).
+//		Do(ctx)
+//	taskId := response.Task
+type ReindexService struct {
+	client            *Client
+	source            *Query
+	remote            *ReindexRemote
+	dest              string
+	destOpType        string
+	destPipeline      string
+	destVersionType   string
+	script            interface{}
+	conflicts         string
+	maxDocs           int64
+	slices            interface{}
+	refresh           bool
+	requestsPerSecond *float64
+	waitForCompletion *bool
+	timeout           string
+}
+
+func NewReindexService(c *Client) *ReindexService {
+	return &ReindexService{
+		client: c,
+	}
+}
+
+func (this *Client) Reindex() *ReindexService {
+	return NewReindexService(this)
+}
+
+// Source sets the documents to copy: those matching the where conditions of
+// query, in its indices, with its _source filter.
+func (this *ReindexService) Source(source *Query) *ReindexService {
+	this.source = source
+	return this
+}
+
+// Remote copies the documents from a remote cluster; its host must be
+// whitelisted in reindex.remote.whitelist.
+func (this *ReindexService) Remote(remote *ReindexRemote) *ReindexService {
+	this.remote = remote
+	return this
+}
+
+// Dest sets the index the documents are copied to.
+func (this *ReindexService) Dest(index string) *ReindexService {
+	this.dest = index
+	return this
+}
+
+// DestOpType set to "create" only copies documents missing in the
+// destination.
+func (this *ReindexService) DestOpType(opType string) *ReindexService {
+	this.destOpType = opType
+	return this
+}
+
+func (this *ReindexService) DestPipeline(pipeline string) *ReindexService {
+	this.destPipeline = pipeline
+	return this
+}
+
+// DestVersionType sets "external" to keep the versions of the source and
+// only copy newer documents.
+func (this *ReindexService) DestVersionType(versionType string) *ReindexService {
+	this.destVersionType = versionType
+	return this
+}
+
+// Script sets a script modifying the documents while copying, see
+// UpdateService.Script.
+func (this *ReindexService) Script(script interface{}) *ReindexService {
+	this.script = script
+	return this
+}
+
+// Conflicts set to "proceed" counts version conflicts instead of aborting.
+func (this *ReindexService) Conflicts(conflicts string) *ReindexService {
+	this.conflicts = conflicts
+	return this
+}
+
+// MaxDocs limits the number of documents copied.
+func (this *ReindexService) MaxDocs(maxDocs int64) *ReindexService {
+	this.maxDocs = maxDocs
+	return this
+}
+
+// Slices splits the reindex into parallel slices: a number or "auto".
+func (this *ReindexService) Slices(slices interface{}) *ReindexService {
+	this.slices = slices
+	return this
+}
+
+// Refresh refreshes the destination index when done.
+func (this *ReindexService) Refresh(refresh bool) *ReindexService {
+	this.refresh = refresh
+	return this
+}
+
+// RequestsPerSecond throttles the reindex; -1 disables throttling.
+func (this *ReindexService) RequestsPerSecond(requestsPerSecond float64) *ReindexService {
+	this.requestsPerSecond = &requestsPerSecond
+	return this
+}
+
+// WaitForCompletion set to false runs the reindex as a task and returns its
+// id in BulkIndexByScrollResponse.Task, see GetTask.
+func (this *ReindexService) WaitForCompletion(waitForCompletion bool) *ReindexService {
+	this.waitForCompletion = &waitForCompletion
+	return this
+}
+
+func (this *ReindexService) Timeout(timeout string) *ReindexService {
+	this.timeout = timeout
+	return this
+}
+
+// Body returns the body of the reindex request.
+func (this *ReindexService) Body() (map[string]interface{}, error) {
+	if this.source == nil || len(this.source.index) == 0 {
+		return nil, fmt.Errorf("reindex requires a source index")
+	}
+	if this.dest == "" {
+		return nil, fmt.Errorf("reindex requires a destination index")
+	}
+	builder := QueryBuilder{}
+	source := map[string]interface{}{"index": this.source.index}
+	if len(this.source.typ) > 0 {
+		source["type"] = this.source.typ
+	}
+	query, err := builder.BuildQuery(this.source)
+	if err != nil {
+		return nil, err
+	}
+	if query != nil {
+		source["query"] = query
+	}
+	if this.source.source != nil {
+		source["_source"] = this.source.source
+	}
+	if this.remote != nil {
+		remote, err := this.remote.Source()
+		if err != nil {
+			return nil, err
+		}
+		source["remote"] = remote
+	}
+
+	dest := map[string]interface{}{"index": this.dest}
+	if this.destOpType != "" {
+		dest["op_type"] = this.destOpType
+	}
+	if this.destPipeline != "" {
+		dest["pipeline"] = this.destPipeline
+	}
+	if this.destVersionType != "" {
+		dest["version_type"] = this.destVersionType
+	}
+
+	body := map[string]interface{}{"source": source, "dest": dest}
+	if this.script != nil {
+		body["script"] = this.script
+	}
+	if this.conflicts != "" {
+		body["conflicts"] = this.conflicts
+	}
+	if this.maxDocs > 0 {
+		body["max_docs"] = this.maxDocs
+	}
+	return body, nil
+}
+
+func (this *ReindexService) buildParams() url.Values {
+	params := url.Values{}
+	if this.slices != nil {
+		params.Set("slices", fmt.Sprint(this.slices))
+	}
+	if this.refresh {
+		params.Set("refresh", "true")
+	}
+	if this.requestsPerSecond != nil {
+		params.Set("requests_per_second", strconv.FormatFloat(*this.requestsPerSecond, 'f', -1, 64))
+	}
+	if this.waitForCompletion != nil {
+		params.Set("wait_for_completion", strconv.FormatBool(*this.waitForCompletion))
+	}
+	if this.timeout != "" {
+		params.Set("timeout", this.timeout)
+	}
+	return params
+}
+
+func (this *ReindexService) Do(ctx context.Context) (*BulkIndexByScrollResponse, error) {
+	body, err := this.Body()
+	if err != nil {
+		return nil, err
+	}
+	response, err := this.client.httpRequest(ctx, "POST", "/_reindex", this.buildParams(), body, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(BulkIndexByScrollResponse)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ReindexRemote is a remote cluster to reindex from.
+type ReindexRemote struct {
+	host           string
+	username       string
+	password       string
+	socketTimeout  string
+	connectTimeout string
+}
+
+// NewReindexRemote returns a remote cluster at host, e.g.
+// "https://es-old.example.com:9200".
+func NewReindexRemote(host string) *ReindexRemote {
+	return &ReindexRemote{
+		host: host,
+	}
+}
+
+func (this *ReindexRemote) BasicAuth(username, password string) *ReindexRemote {
+	this.username = username
+	this.password = password
+	return this
+}
+
+func (this *ReindexRemote) SocketTimeout(timeout string) *ReindexRemote {
+	this.socketTimeout = timeout
+	return this
+}
+
+func (this *ReindexRemote) ConnectTimeout(timeout string) *ReindexRemote {
+	this.connectTimeout = timeout
+	return this
+}
+
+func (this *ReindexRemote) Source() (interface{}, error) {
+	if this.host == "" {
+		return nil, fmt.Errorf("remote reindex requires a host")
+	}
+	source := map[string]interface{}{"host": this.host}
+	if this.username != "" {
+		source["username"] = this.username
+		source["password"] = this.password
+	}
+	if this.socketTimeout != "" {
+		source["socket_timeout"] = this.socketTimeout
+	}
+	if this.connectTimeout != "" {
+		source["connect_timeout"] = this.connectTimeout
+	}
+	return source, nil
+}
+
+// BulkIndexByScrollResponse is the response of a reindex, update by query
+// or delete by query. With WaitForCompletion(false) only Task is set, the id
+// of the task running the request.
+type BulkIndexByScrollResponse struct {
+	Task                 string                     `json:"task,omitempty"`
+	Took                 int64                      `json:"took"`
+	TimedOut             bool                       `json:"timed_out"`
+	Total                int64                      `json:"total"`
+	Created              int64                      `json:"created"`
+	Updated              int64                      `json:"updated"`
+	Deleted              int64                      `json:"deleted"`
+	Batches              int64                      `json:"batches"`
+	VersionConflicts     int64                      `json:"version_conflicts"`
+	Noops                int64                      `json:"noops"`
+	Retries              BulkIndexByScrollRetries   `json:"retries"`
+	ThrottledMillis      int64                      `json:"throttled_millis"`
+	RequestsPerSecond    float64                    `json:"requests_per_second"`
+	ThrottledUntilMillis int64                      `json:"throttled_until_millis"`
+	Failures             []BulkIndexByScrollFailure `json:"failures,omitempty"`
+}
+
+// BulkIndexByScrollRetries counts the retries of rejected bulk and search
+// requests.
+type BulkIndexByScrollRetries struct {
+	Bulk   int64 `json:"bulk"`
+	Search int64 `json:"search"`
+}
+
+// BulkIndexByScrollFailure is a document or shard that failed.
+type BulkIndexByScrollFailure struct {
+	Index  string        `json:"index,omitempty"`
+	Id     string        `json:"id,omitempty"`
+	Shard  *int          `json:"shard,omitempty"`
+	Node   string        `json:"node,omitempty"`
+	Status int           `json:"status,omitempty"`
+	Cause  *ErrorDetails `json:"cause,omitempty"`
+	Reason *ErrorDetails `json:"reason,omitempty"`
+}
@@ -0,0 +1,107 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestReindex(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_reindex" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.RawQuery != "slices=auto&wait_for_completion=false" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		expected := `{"conflicts":"proceed","dest":{"index":"md_fin_waybill_v2","op_type":"create"},` +
+			`"source":{"index":["md_fin_waybill"],"query":{"bool":{"must":[{"range":{"F_FJScan_Flag":{"lt":"1"}}}]}},` +
+			`"remote":{"host":"http://es-old:9200","password":"secret","username":"elastic"}}}`
+		if string(body) != expected {
+			t.Errorf("expected body\n%s\ngot\n%s", expected, string(body))
+		}
+		w.Write([]byte(`{"task": "oTUltX4IQMOUUVeiohTt8A:12345"}`))
+	})
+	response, err := client.Reindex().
+		Source(client.Search("md_fin_waybill").AndWhere("<", "F_FJScan_Flag", "1")).
+		Remote(NewReindexRemote("http://es-old:9200").BasicAuth("elastic", "secret")).
+		Dest("md_fin_waybill_v2").
+		DestOpType("create").
+		Conflicts("proceed").
+		Slices("auto").
+		WaitForCompletion(false).
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Task != "oTUltX4IQMOUUVeiohTt8A:12345" {
+		t.Errorf("unexpected task %q", response.Task)
+	}
+}
+
+func TestUpdateByQuery(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/md_fin_waybill/_update_by_query" || r.URL.Query().Get("conflicts") != "proceed" {
+			t.Errorf("unexpected request %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		expected := `{"query":{"bool":{"must":[{"range":{"F_FJScan_Flag":{"lt":"1"}}}]}},"script":{"source":"ctx._source.F_FJScan_Flag = 1"}}`
+		if string(body) != expected {
+			t.Errorf("expected body\n%s\ngot\n%s", expected, string(body))
+		}
+		w.Write([]byte(`{"took": 147, "timed_out": false, "total": 5, "updated": 4, "version_conflicts": 1, "batches": 1,
+			"retries": {"bulk": 0, "search": 0}, "failures": []}`))
+	})
+	response, err := client.UpdateByQuery().
+		Query(client.Search("md_fin_waybill").AndWhere("<", "F_FJScan_Flag", "1")).
+		Script(map[string]interface{}{"source": "ctx._source.F_FJScan_Flag = 1"}).
+		Conflicts("proceed").
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Updated != 4 || response.VersionConflicts != 1 {
+		t.Errorf("unexpected response %+v", response)
+	}
+}
+
+func TestDeleteByQuery(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/md_fin_waybill/_delete_by_query" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.RawQuery != "conflicts=proceed&scroll_size=500&slices=auto&wait_for_completion=true" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		expected := `{"max_docs":1000,"query":{"match_all":{}}}`
+		if string(body) != expected {
+			t.Errorf("expected body\n%s\ngot\n%s", expected, string(body))
+		}
+		w.Write([]byte(`{"took": 147, "timed_out": false, "total": 5, "deleted": 5, "batches": 1,
+			"retries": {"bulk": 0, "search": 0}, "failures": []}`))
+	})
+	response, err := client.DeleteByQuery().
+		Query(client.Search("md_fin_waybill").Query(map[string]interface{}{"match_all": map[string]interface{}{}})).
+		Conflicts("proceed").
+		MaxDocs(1000).
+		Slices("auto").
+		ScrollSize(500).
+		WaitForCompletion(true).
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Deleted != 5 {
+		t.Errorf("unexpected response %+v", response)
+	}
+}
+
+func TestDeleteByQueryRequiresQuery(t *testing.T) {
+	client, _ := NewClient()
+	if _, err := client.DeleteByQuery("md_fin_waybill").Do(context.Background()); err == nil {
+		t.Error("expected error for a delete by query without query")
+	}
+}
@@ -26,8 +26,15 @@ func (this *Request)SetBasicAuth(username,password string)  {
 	((*http.Request)(this)).SetBasicAuth(username,password)
 }
 
+// NDJSON is a request body of newline-delimited JSON, as expected by e.g. the
+// _msearch endpoint. Every element is encoded as JSON on its own line.
+type NDJSON []interface{}
+
 //setBodyJson encodes the body as a struct to be marshaled via json.Marshal.
 func (this *Request)SetBody(data interface{}) error {
+	if lines, ok := data.(NDJSON); ok {
+		return this.setBodyNDJSON(lines)
+	}
 	body, err := json.Marshal(data)
 	if err != nil{
 		return err
@@ -36,6 +43,23 @@ func (this *Request)SetBody(data interface{}) error {
 	this.setBodyReader(bytes.NewReader(body))
 	return nil
 }
+
+// setBodyNDJSON encodes every line via json.Marshal, terminating each with a newline.
+func (this *Request) setBodyNDJSON(lines NDJSON) error {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		body, err := json.Marshal(line)
+		if err != nil {
+			return err
+		}
+		buf.Write(body)
+		buf.WriteByte('\n')
+	}
+	this.Header.Set("Content-Type", "application/x-ndjson")
+	this.setBodyReader(&buf)
+	return nil
+}
+
 // setBodyReader writes the body from an io.Reader.
 func (this *Request)setBodyReader(body io.Reader) {
 	rc,ok := body.(io.ReadCloser)
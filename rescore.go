@@ -0,0 +1,77 @@
+package go_elasticsearch
+
+import "fmt"
+
+// Rescore re-scores the top hits of a search, per shard, with a second,
+// usually more expensive, query.
+//
+//	client.Search("md_dispatch").AndWhere("in", "F_Status", "open").Rescore(
+//		NewRescore(NewFunctionScoreQuery().AddScoreFunc(NewGaussDecayFunction("F_Location").Origin(origin).Scale("10km"))).
+//			WindowSize(100),
+//	)
+type Rescore struct {
+	query              interface{}
+	windowSize         int
+	queryWeight        *float64
+	rescoreQueryWeight *float64
+	scoreMode          string
+}
+
+// NewRescore returns a rescorer using query, which is either a *Query whose
+// where conditions are used, a typed query such as *FunctionScoreQuery, or
+// a raw query.
+func NewRescore(query interface{}) *Rescore {
+	return &Rescore{
+		query: query,
+	}
+}
+
+// WindowSize sets the number of top hits per shard that are rescored.
+func (this *Rescore) WindowSize(windowSize int) *Rescore {
+	this.windowSize = windowSize
+	return this
+}
+
+// QueryWeight sets the weight of the score of the original query.
+func (this *Rescore) QueryWeight(weight float64) *Rescore {
+	this.queryWeight = &weight
+	return this
+}
+
+// RescoreQueryWeight sets the weight of the score of the rescore query.
+func (this *Rescore) RescoreQueryWeight(weight float64) *Rescore {
+	this.rescoreQueryWeight = &weight
+	return this
+}
+
+// ScoreMode sets how the scores are combined: "total", "multiply", "avg",
+// "max" or "min".
+func (this *Rescore) ScoreMode(scoreMode string) *Rescore {
+	this.scoreMode = scoreMode
+	return this
+}
+
+func (this *Rescore) Source() (interface{}, error) {
+	query, err := buildQueryValue(this.query)
+	if err != nil {
+		return nil, err
+	}
+	if query == nil {
+		return nil, fmt.Errorf("rescore requires a query")
+	}
+	options := map[string]interface{}{"rescore_query": query}
+	if this.queryWeight != nil {
+		options["query_weight"] = *this.queryWeight
+	}
+	if this.rescoreQueryWeight != nil {
+		options["rescore_query_weight"] = *this.rescoreQueryWeight
+	}
+	if this.scoreMode != "" {
+		options["score_mode"] = this.scoreMode
+	}
+	source := map[string]interface{}{"query": options}
+	if this.windowSize > 0 {
+		source["window_size"] = this.windowSize
+	}
+	return source, nil
+}
@@ -0,0 +1,214 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ScrollService iterates over all documents matching a query with the
+// Scroll API, beyond the from+size window available to Query.
+//
+//	scroll := client.Scroll("md_fin_waybill").Size(1000).AndWhere("between", "F_OrderTime", from, to)
+//	err := scroll.Each(ctx, func(hit *SearchHit) error { ... })
+type ScrollService struct {
+	client    *Client
+	query     *Query
+	keepAlive string
+	scrollId  string
+	done      bool
+}
+
+func NewScrollService(c *Client) *ScrollService {
+	return &ScrollService{
+		client:    c,
+		query:     NewQuery(c),
+		keepAlive: "1m",
+	}
+}
+
+// Scroll returns a scroll over the given indices.
+func (this *Client) Scroll(indexs ...string) *ScrollService {
+	return NewScrollService(this).Index(indexs...)
+}
+
+func (this *ScrollService) Index(index ...string) *ScrollService {
+	this.query.Index(index...)
+	return this
+}
+
+func (this *ScrollService) Type(typ ...string) *ScrollService {
+	this.query.Type(typ...)
+	return this
+}
+
+// Query replaces the query to scroll over, e.g. one created by Client.Search.
+func (this *ScrollService) Query(query *Query) *ScrollService {
+	this.query = query
+	return this
+}
+
+func (this *ScrollService) AndWhere(condition ...interface{}) *ScrollService {
+	this.query.AndWhere(condition...)
+	return this
+}
+
+func (this *ScrollService) OrWhere(condition ...interface{}) *ScrollService {
+	this.query.OrWhere(condition...)
+	return this
+}
+
+// OrderBy sorts the hits. Without a sort, hits are returned in index order
+// ("_doc"), which is the most efficient order for scrolling.
+func (this *ScrollService) OrderBy(orderBy ...map[string]string) *ScrollService {
+	this.query.OrderBy(orderBy...)
+	return this
+}
+
+func (this *ScrollService) Source(source interface{}) *ScrollService {
+	this.query.Source(source)
+	return this
+}
+
+// Size sets the number of hits returned per page.
+func (this *ScrollService) Size(size int) *ScrollService {
+	this.query.Limit(size)
+	return this
+}
+
+// Slice restricts the scroll to the slice with the given id out of max
+// slices. See SlicedExport to consume all slices in parallel.
+func (this *ScrollService) Slice(id, max int) *ScrollService {
+	this.query.Slice(id, max)
+	return this
+}
+
+// KeepAlive sets how long the scroll context is kept alive between two
+// pages, e.g. "5m". The default is "1m".
+func (this *ScrollService) KeepAlive(keepAlive string) *ScrollService {
+	this.keepAlive = keepAlive
+	return this
+}
+
+// ScrollId returns the id of the open scroll context, if any.
+func (this *ScrollService) ScrollId() string {
+	return this.scrollId
+}
+
+// Next returns the next page of hits. It returns io.EOF once all hits have
+// been returned, after clearing the scroll context. On error the scroll
+// context is cleared as well, on a best-effort basis.
+func (this *ScrollService) Next(ctx context.Context) (*SearchResult, error) {
+	if this.done {
+		return nil, io.EOF
+	}
+	var (
+		response *Response
+		err      error
+	)
+	if this.scrollId == "" {
+		response, err = this.first(ctx)
+	} else {
+		response, err = this.client.httpRequest(ctx, "POST", "/_search/scroll", nil, map[string]interface{}{
+			"scroll":    this.keepAlive,
+			"scroll_id": this.scrollId,
+		}, false)
+	}
+	if err != nil {
+		this.clearQuietly()
+		return nil, err
+	}
+	result, err := newSearchResult(response)
+	if err != nil {
+		this.clearQuietly()
+		return nil, err
+	}
+	if result.ScrollId != "" {
+		this.scrollId = result.ScrollId
+	}
+	if len(result.Hits.Hits) == 0 {
+		this.done = true
+		if err := this.Clear(ctx); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	return result, nil
+}
+
+// Each calls fn for every hit until all hits are consumed, fn returns an
+// error or ctx is done. The scroll context is always cleared on return.
+func (this *ScrollService) Each(ctx context.Context, fn func(hit *SearchHit) error) (err error) {
+	defer func() {
+		// ctx may be done already, so clear with a fresh context
+		if clearErr := this.Clear(context.Background()); err == nil {
+			err = clearErr
+		}
+	}()
+	for {
+		result, err := this.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, hit := range result.Hits.Hits {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(hit); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Clear releases the scroll context on the server. Next returns io.EOF
+// afterwards.
+func (this *ScrollService) Clear(ctx context.Context) error {
+	this.done = true
+	if this.scrollId == "" {
+		return nil
+	}
+	scrollId := this.scrollId
+	this.scrollId = ""
+	_, err := this.client.httpRequest(ctx, "DELETE", "/_search/scroll", nil, map[string]interface{}{
+		"scroll_id": []string{scrollId},
+	}, false)
+	if e, ok := err.(*Error); ok && e.Status == http.StatusNotFound {
+		// the scroll context has expired already
+		return nil
+	}
+	return err
+}
+
+func (this *ScrollService) first(ctx context.Context) (*Response, error) {
+	builder := QueryBuilder{}
+	path, params, err := this.query.BuildUrl()
+	if err != nil {
+		return nil, err
+	}
+	body, err := builder.Build(this.query)
+	if err != nil {
+		return nil, err
+	}
+	if len(this.query.orderBy) == 0 {
+		body["sort"] = []string{"_doc"}
+	}
+	// from cannot be used in a scroll context, the scroll pages from the start
+	delete(body, "from")
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("scroll", this.keepAlive)
+	return this.client.httpRequest(ctx, "POST", path, params, body, false)
+}
+
+// clearQuietly clears the scroll context after a failed request, ignoring
+// the error as the scroll context expires anyway. ctx may be done already,
+// so it clears with a fresh context.
+func (this *ScrollService) clearQuietly() {
+	_ = this.Clear(context.Background())
+}
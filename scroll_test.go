@@ -0,0 +1,119 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestScrollEach(t *testing.T) {
+	var (
+		pages   = []string{`[{"_id": "1"}, {"_id": "2"}]`, `[{"_id": "3"}]`, `[]`}
+		page    = 0
+		cleared = 0
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "DELETE" && r.URL.Path == "/_search/scroll":
+			cleared++
+			w.Write([]byte(`{"succeeded": true, "num_freed": 1}`))
+			return
+		case page == 0 && r.URL.Path == "/md_fin_waybill/_search":
+			if r.URL.Query().Get("scroll") != "5m" {
+				t.Errorf("expected scroll=5m, got %q", r.URL.RawQuery)
+			}
+		case page > 0 && r.URL.Path == "/_search/scroll":
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		w.Write([]byte(`{"_scroll_id": "abc", "hits": {"hits": ` + pages[page] + `}}`))
+		page++
+	})
+
+	ids := make([]string, 0)
+	err := client.Scroll("md_fin_waybill").KeepAlive("5m").Size(2).Each(context.Background(), func(hit *SearchHit) error {
+		ids = append(ids, hit.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3 {
+		t.Errorf("expected 3 hits, got %v", ids)
+	}
+	if cleared != 1 {
+		t.Errorf("expected scroll to be cleared once, got %d", cleared)
+	}
+}
+
+func TestScrollEachClearsOnError(t *testing.T) {
+	cleared := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			cleared++
+			return
+		}
+		w.Write([]byte(`{"_scroll_id": "abc", "hits": {"hits": [{"_id": "1"}]}}`))
+	})
+	stop := errors.New("stop")
+	err := client.Scroll("md_fin_waybill").Each(context.Background(), func(hit *SearchHit) error {
+		return stop
+	})
+	if err != stop {
+		t.Errorf("expected %v, got %v", stop, err)
+	}
+	if cleared != 1 {
+		t.Errorf("expected scroll to be cleared once, got %d", cleared)
+	}
+}
+
+func TestScrollNextClearsOnError(t *testing.T) {
+	var (
+		page    = 0
+		cleared = 0
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			cleared++
+			return
+		}
+		if page > 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error": {"type": "search_phase_execution_exception"}, "status": 500}`))
+			return
+		}
+		page++
+		w.Write([]byte(`{"_scroll_id": "abc", "hits": {"hits": [{"_id": "1"}]}}`))
+	})
+	scroll := client.Scroll("md_fin_waybill")
+	if _, err := scroll.Next(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := scroll.Next(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+	if cleared != 1 || scroll.ScrollId() != "" {
+		t.Errorf("expected scroll to be cleared once, got %d", cleared)
+	}
+}
+
+func TestScrollIgnoresOffset(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			return
+		}
+		body := make(map[string]interface{})
+		json.NewDecoder(r.Body).Decode(&body)
+		if _, ok := body["from"]; ok {
+			t.Errorf("expected no from, got %v", body)
+		}
+		w.Write([]byte(`{"_scroll_id": "abc", "hits": {"hits": []}}`))
+	})
+	query := client.Search("md_fin_waybill").Offset(20)
+	if _, err := NewScrollService(client).Query(query).Next(context.Background()); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"io"
+)
+
+// SearchAfterPaginator pages through all hits of a query with search_after
+// on a point in time, which, unlike Query.Offset, is not limited to the
+// first 10000 hits. The point in time is opened on the first call to Next
+// and closed once all hits have been returned, or by Close.
+//
+//	query := client.Search("md_fin_waybill").Limit(100).OrderBy(map[string]string{"F_OrderTime": "desc"})
+//	pages := NewSearchAfterPaginator(query).KeepAlive("5m")
+//	defer pages.Close(ctx)
+//	result, err := pages.Next(ctx)
+type SearchAfterPaginator struct {
+	query      *Query
+	keepAlive  string
+	tiebreaker string
+	pitId      string
+	ownsPit    bool
+	after      []interface{}
+	done       bool
+}
+
+func NewSearchAfterPaginator(query *Query) *SearchAfterPaginator {
+	return &SearchAfterPaginator{
+		query:      query,
+		keepAlive:  "1m",
+		tiebreaker: "_shard_doc",
+		after:      query.searchAfter,
+	}
+}
+
+// KeepAlive sets how long the point in time is kept alive between two pages.
+// The default is "1m".
+func (this *SearchAfterPaginator) KeepAlive(keepAlive string) *SearchAfterPaginator {
+	this.keepAlive = keepAlive
+	return this
+}
+
+// Tiebreaker sets the field appended to the sort of the query, if missing,
+// to give every hit a unique sort value. The default is "_shard_doc".
+func (this *SearchAfterPaginator) Tiebreaker(field string) *SearchAfterPaginator {
+	this.tiebreaker = field
+	return this
+}
+
+// PointInTime uses an already opened point in time. It is not closed by the
+// paginator.
+func (this *SearchAfterPaginator) PointInTime(id string) *SearchAfterPaginator {
+	this.pitId = id
+	this.ownsPit = false
+	return this
+}
+
+// After continues after the given sort values, as returned by Cursor.
+func (this *SearchAfterPaginator) After(sortValues ...interface{}) *SearchAfterPaginator {
+	this.after = sortValues
+	return this
+}
+
+// Cursor returns the sort values of the last hit returned, to be passed to
+// After when resuming, e.g. on the next request of a UI.
+func (this *SearchAfterPaginator) Cursor() []interface{} {
+	return this.after
+}
+
+// PitId returns the id of the point in time in use.
+func (this *SearchAfterPaginator) PitId() string {
+	return this.pitId
+}
+
+// Next returns the next page of hits. It returns io.EOF once all hits have
+// been returned.
+func (this *SearchAfterPaginator) Next(ctx context.Context) (*SearchResult, error) {
+	if this.done {
+		return nil, io.EOF
+	}
+	if this.pitId == "" {
+		id, err := this.query.client.OpenPointInTime(ctx, this.keepAlive, this.query.index...)
+		if err != nil {
+			return nil, err
+		}
+		this.pitId = id
+		this.ownsPit = true
+	}
+
+	query := this.query.clone()
+	// from cannot be combined with search_after, the pages follow the cursor
+	query.offset = 0
+	query.PointInTime(this.pitId, this.keepAlive).SearchAfter(this.after...)
+	if this.tiebreaker != "" && !hasSort(query.orderBy, this.tiebreaker) {
+		query.OrderBy(map[string]string{this.tiebreaker: "asc"})
+	}
+	result, err := query.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if result.PitId != "" {
+		this.pitId = result.PitId
+	}
+	hits := result.Hits.Hits
+	if len(hits) == 0 {
+		return nil, this.finish(ctx)
+	}
+	this.after = hits[len(hits)-1].Sort
+	if query.limit > 0 && len(hits) < query.limit {
+		// this is the last page, no need to ask for an empty one
+		if err := this.finish(ctx); err != io.EOF {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Each calls fn for every hit until all hits are consumed, fn returns an
+// error or ctx is done. The point in time is always closed on return.
+func (this *SearchAfterPaginator) Each(ctx context.Context, fn func(hit *SearchHit) error) (err error) {
+	defer func() {
+		// ctx may be done already, so close with a fresh context
+		if closeErr := this.Close(context.Background()); err == nil {
+			err = closeErr
+		}
+	}()
+	for {
+		result, err := this.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, hit := range result.Hits.Hits {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(hit); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Close closes the point in time if it was opened by the paginator. Next
+// returns io.EOF afterwards.
+func (this *SearchAfterPaginator) Close(ctx context.Context) error {
+	this.done = true
+	if !this.ownsPit || this.pitId == "" {
+		return nil
+	}
+	pitId := this.pitId
+	this.pitId = ""
+	this.ownsPit = false
+	return this.query.client.ClosePointInTime(ctx, pitId)
+}
+
+func (this *SearchAfterPaginator) finish(ctx context.Context) error {
+	if err := this.Close(ctx); err != nil {
+		return err
+	}
+	return io.EOF
+}
+
+func hasSort(orderBy []map[string]string, field string) bool {
+	for _, sort := range orderBy {
+		if _, ok := sort[field]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+type searchAfterTestBody struct {
+	From        *int                `json:"from"`
+	Pit         PointInTime         `json:"pit"`
+	SearchAfter json.RawMessage     `json:"search_after"`
+	Sort        []map[string]string `json:"sort"`
+}
+
+func TestSearchAfterPaginatorEach(t *testing.T) {
+	var (
+		pages = []struct {
+			pitId       string
+			searchAfter string
+			response    string
+		}{
+			{"pit-1", ``, `{"pit_id": "pit-2", "hits": {"hits": [{"_id": "1", "sort": [1583539200000, 7]}, {"_id": "2", "sort": [1583539200000, 9]}]}}`},
+			{"pit-2", `[1583539200000,9]`, `{"pit_id": "pit-3", "hits": {"hits": [{"_id": "3", "sort": [1583452800000, 1]}, {"_id": "4", "sort": [1583452800000, 4]}]}}`},
+			{"pit-3", `[1583452800000,4]`, `{"pit_id": "pit-3", "hits": {"hits": []}}`},
+		}
+		page   = 0
+		closed = make([]string, 0)
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/md_fin_waybill/_pit":
+			if r.URL.Query().Get("keep_alive") != "5m" {
+				t.Errorf("expected keep_alive=5m, got %q", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"id": "pit-1"}`))
+		case r.Method == "DELETE" && r.URL.Path == "/_pit":
+			body := struct {
+				Id string `json:"id"`
+			}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			closed = append(closed, body.Id)
+		case r.URL.Path == "/_search":
+			if page >= len(pages) {
+				t.Errorf("unexpected page %d", page)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body := searchAfterTestBody{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			expected := pages[page]
+			if body.Pit.Id != expected.pitId || body.Pit.KeepAlive != "5m" {
+				t.Errorf("page %d: expected point in time %s, got %+v", page, expected.pitId, body.Pit)
+			}
+			if string(body.SearchAfter) != expected.searchAfter {
+				t.Errorf("page %d: expected search_after %s, got %s", page, expected.searchAfter, body.SearchAfter)
+			}
+			if body.From != nil {
+				t.Errorf("page %d: expected no from, got %d", page, *body.From)
+			}
+			sort := []map[string]string{{"F_OrderTime": "desc"}, {"_shard_doc": "asc"}}
+			if !reflect.DeepEqual(body.Sort, sort) {
+				t.Errorf("page %d: expected sort %v, got %v", page, sort, body.Sort)
+			}
+			w.Write([]byte(expected.response))
+			page++
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	query := client.Search("md_fin_waybill").Offset(20).Limit(2).OrderBy(map[string]string{"F_OrderTime": "desc"})
+	ids := make([]string, 0)
+	err := NewSearchAfterPaginator(query).KeepAlive("5m").Each(context.Background(), func(hit *SearchHit) error {
+		ids = append(ids, hit.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 4 || page != 3 {
+		t.Errorf("expected 4 hits in 3 pages, got %v in %d", ids, page)
+	}
+	if !reflect.DeepEqual(closed, []string{"pit-3"}) {
+		t.Errorf("expected the last point in time to be closed once, got %v", closed)
+	}
+	if len(query.orderBy) != 1 || query.pointInTime != nil || query.offset != 20 {
+		t.Errorf("the query was modified")
+	}
+}
+
+func TestSearchAfterPaginatorTiebreaker(t *testing.T) {
+	var sorts [][]map[string]string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_search" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			return
+		}
+		body := searchAfterTestBody{}
+		json.NewDecoder(r.Body).Decode(&body)
+		sorts = append(sorts, body.Sort)
+		w.Write([]byte(`{"hits": {"hits": []}}`))
+	})
+
+	// the tiebreaker is already part of the sort
+	query := client.Search("md_fin_waybill").OrderBy(map[string]string{"F_OrderTime": "desc"}, map[string]string{"F_Id": "asc"})
+	if _, err := NewSearchAfterPaginator(query).PointInTime("pit-1").Tiebreaker("F_Id").Next(context.Background()); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+	// the tiebreaker is missing
+	query = client.Search("md_fin_waybill").OrderBy(map[string]string{"F_OrderTime": "desc"})
+	if _, err := NewSearchAfterPaginator(query).PointInTime("pit-1").Tiebreaker("F_Id").Next(context.Background()); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+	expected := [][]map[string]string{
+		{{"F_OrderTime": "desc"}, {"F_Id": "asc"}},
+		{{"F_OrderTime": "desc"}, {"F_Id": "asc"}},
+	}
+	if !reflect.DeepEqual(sorts, expected) {
+		t.Errorf("expected sorts %v, got %v", expected, sorts)
+	}
+}
+
+func TestSearchAfterPaginatorClosesOnError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		fn     func(hit *SearchHit) error
+	}{
+		{"search error", http.StatusInternalServerError, func(hit *SearchHit) error { return nil }},
+		{"callback error", http.StatusOK, func(hit *SearchHit) error { return errors.New("stop") }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			closed := 0
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/md_fin_waybill/_pit":
+					w.Write([]byte(`{"id": "pit-1"}`))
+				case r.Method == "DELETE" && r.URL.Path == "/_pit":
+					closed++
+				default:
+					w.WriteHeader(test.status)
+					w.Write([]byte(`{"hits": {"hits": [{"_id": "1", "sort": [1]}]}}`))
+				}
+			})
+			err := NewSearchAfterPaginator(client.Search("md_fin_waybill")).Each(context.Background(), test.fn)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if closed != 1 {
+				t.Errorf("expected the point in time to be closed once, got %d", closed)
+			}
+		})
+	}
+}
+
+func TestOpenPointInTimeRequiresIndex(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL)
+	})
+	if _, err := client.OpenPointInTime(context.Background(), "1m"); err == nil {
+		t.Error("expected error opening a point in time without index")
+	}
+}
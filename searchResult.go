@@ -0,0 +1,134 @@
+package go_elasticsearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+)
+
+// SearchResult is the result of a search in Elasticsearch. It understands
+// both the legacy (6.x) and the modern (7.x, 8.x) response shapes.
+type SearchResult struct {
+	Header  http.Header     `json:"-"` // the HTTP header of the response
+	RawBody json.RawMessage `json:"-"` // the undecoded response body
+
+	ScrollId     string           `json:"_scroll_id,omitempty"` // only used with the Scroll API
+	PitId        string           `json:"pit_id,omitempty"`     // only used when searching a point in time
+	Took         int64            `json:"took"`
+	TimedOut     bool             `json:"timed_out"`
+	Shards       ShardsInfo       `json:"_shards"`
+	Hits         SearchHits       `json:"hits"`
+	Aggregations *json.RawMessage `json:"aggregations,omitempty"` // results from aggregations
+	// Suggest holds the suggestions of every suggester, see Query.Suggest.
+	Suggest map[string][]SearchSuggestion `json:"suggest,omitempty"`
+	// Profile holds the timings of the search, see Query.Profile.
+	Profile *SearchProfile `json:"profile,omitempty"`
+
+	// Status and Error are only set for failed searches of a multi search.
+	Status int           `json:"status,omitempty"`
+	Error  *ErrorDetails `json:"error,omitempty"`
+}
+
+// newSearchResult decodes the body of a search response. Numbers in sort
+// values are kept as json.Number, so that they can be passed back to
+// search_after without losing precision.
+func newSearchResult(response *Response) (*SearchResult, error) {
+	result := new(SearchResult)
+	decoder := json.NewDecoder(bytes.NewReader(response.Body))
+	decoder.UseNumber()
+	if err := decoder.Decode(result); err != nil {
+		return nil, err
+	}
+	result.Header = response.Header
+	result.RawBody = response.Body
+	return result, nil
+}
+
+// TotalHits returns the total number of hits, or 0 if Elasticsearch did not
+// count them (see Query.TrackTotalHits).
+func (this *SearchResult) TotalHits() int64 {
+	if this.Hits.Total != nil {
+		return this.Hits.Total.Value
+	}
+	return 0
+}
+
+// ShardsInfo tells how many shards took part in a request.
+type ShardsInfo struct {
+	Total      int64           `json:"total"`
+	Successful int64           `json:"successful"`
+	Skipped    int64           `json:"skipped,omitempty"`
+	Failed     int64           `json:"failed"`
+	Failures   []*ShardFailure `json:"failures,omitempty"`
+}
+
+// ShardFailure describes why a request failed on a shard.
+type ShardFailure struct {
+	Shard  int           `json:"shard"`
+	Index  string        `json:"index,omitempty"`
+	Node   string        `json:"node,omitempty"`
+	Reason *ErrorDetails `json:"reason,omitempty"`
+}
+
+// SearchHits is the list of hits of a search.
+type SearchHits struct {
+	// Total is nil if Elasticsearch did not count the hits.
+	Total    *TotalHits   `json:"total,omitempty"`
+	MaxScore *float64     `json:"max_score"` // nil if the hits are not scored
+	Hits     []*SearchHit `json:"hits"`
+}
+
+// TotalHits is the total number of hits of a search. Relation is "eq" if
+// Value is accurate, or "gte" if Value is a lower bound.
+type TotalHits struct {
+	Value    int64  `json:"value"`
+	Relation string `json:"relation"`
+}
+
+// UnmarshalJSON decodes both a plain number, as returned by Elasticsearch
+// 6.x and earlier, and the {"value": n, "relation": "eq"} object of 7.x.
+func (this *TotalHits) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] != '{' {
+		this.Relation = "eq"
+		return json.Unmarshal(data, &this.Value)
+	}
+	total := struct {
+		Value    int64  `json:"value"`
+		Relation string `json:"relation"`
+	}{}
+	if err := json.Unmarshal(data, &total); err != nil {
+		return err
+	}
+	this.Value, this.Relation = total.Value, total.Relation
+	return nil
+}
+
+// SearchHit is a single hit.
+type SearchHit struct {
+	ID      string           `json:"_id"`
+	Index   string           `json:"_index"`
+	Type    string           `json:"_type,omitempty"` // removed in Elasticsearch 8
+	Score   *float64         `json:"_score"`          // nil if the hit is not scored, e.g. when sorting
+	Source  *json.RawMessage `json:"_source"`
+	Version *int64           `json:"_version,omitempty"` // only returned if Query.Version(true) was set
+	Sort    []interface{}    `json:"sort,omitempty"`
+	// Fields holds the stored, docvalue and script fields requested.
+	Fields map[string][]interface{} `json:"fields,omitempty"`
+	// Highlight holds the highlighted fragments per field, see Query.Highlight.
+	Highlight map[string][]string `json:"highlight,omitempty"`
+	// InnerHits holds the inner hits by name, e.g. of a collapsed field.
+	InnerHits map[string]*SearchHitInnerHits `json:"inner_hits,omitempty"`
+	// Explanation explains the score of the hit, see Query.Explain.
+	Explanation *SearchExplanation `json:"_explanation,omitempty"`
+	Shard       string             `json:"_shard,omitempty"` // only returned with explanations
+	Node        string             `json:"_node,omitempty"`  // only returned with explanations
+}
+
+// SearchHitInnerHits are the inner hits of a hit.
+type SearchHitInnerHits struct {
+	Hits SearchHits `json:"hits"`
+}
@@ -0,0 +1,59 @@
+package go_elasticsearch
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSearchResultShapes(t *testing.T) {
+	tests := []struct {
+		body     string
+		total    int64
+		relation string
+		maxScore *float64
+	}{
+		// #0: Elasticsearch 6.x
+		{
+			`{"took": 3, "hits": {"total": 42, "max_score": 1.5, "hits": [{"_id": "1", "_index": "md_fin_waybill", "_type": "md_fin_waybill", "_score": 1.5}]}}`,
+			42, "eq", floatPtr(1.5),
+		},
+		// #1: Elasticsearch 7.x
+		{
+			`{"took": 3, "hits": {"total": {"value": 10000, "relation": "gte"}, "max_score": null, "hits": [{"_id": "1", "_index": "md_fin_waybill", "_score": null, "sort": [1]}]}}`,
+			10000, "gte", nil,
+		},
+		// #2: track_total_hits disabled
+		{
+			`{"took": 3, "hits": {"max_score": 0.2, "hits": [{"_id": "1", "_index": "md_fin_waybill", "_score": 0.2}]}}`,
+			0, "", floatPtr(0.2),
+		},
+	}
+	for i, test := range tests {
+		result := new(SearchResult)
+		if err := json.Unmarshal([]byte(test.body), result); err != nil {
+			t.Errorf("test %d: %v", i, err)
+			continue
+		}
+		if result.TotalHits() != test.total {
+			t.Errorf("test %d: expected total %d, got %d", i, test.total, result.TotalHits())
+		}
+		if result.Hits.Total != nil && result.Hits.Total.Relation != test.relation {
+			t.Errorf("test %d: expected relation %q, got %q", i, test.relation, result.Hits.Total.Relation)
+		}
+		if (result.Hits.MaxScore == nil) != (test.maxScore == nil) ||
+			(test.maxScore != nil && *result.Hits.MaxScore != *test.maxScore) {
+			t.Errorf("test %d: expected max score %v, got %v", i, test.maxScore, result.Hits.MaxScore)
+		}
+		if len(result.Hits.Hits) != 1 {
+			t.Fatalf("test %d: expected 1 hit, got %d", i, len(result.Hits.Hits))
+		}
+		hit := result.Hits.Hits[0]
+		if (hit.Score == nil) != (test.maxScore == nil) {
+			t.Errorf("test %d: expected score %v, got %v", i, test.maxScore, hit.Score)
+		}
+	}
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
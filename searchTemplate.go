@@ -0,0 +1,331 @@
+package go_elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/wh5231/go-elasticsearch/uritemplates"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// StoredScript is a script stored with PutSearchTemplate, e.g. a mustache
+// search template.
+type StoredScript struct {
+	Lang   string `json:"lang"`
+	Source string `json:"source"`
+}
+
+// PutSearchTemplate stores a mustache search template under id. The source
+// is the template as a string, or a search body with "{{param}}"
+// placeholders.
+//
+//	client.PutSearchTemplate(ctx, "waybill_by_flag", map[string]interface{}{
+//		"query": map[string]interface{}{"term": map[string]interface{}{"F_FJScan_Flag": "{{flag}}"}},
+//		"size":  "{{size}}{{^size}}10{{/size}}",
+//	})
+func (this *Client) PutSearchTemplate(ctx context.Context, id string, source interface{}) error {
+	path, err := uritemplates.Expand("/_scripts/{id}", map[string]string{
+		"id": id,
+	})
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"script": map[string]interface{}{"lang": "mustache", "source": source},
+	}
+	_, err = this.httpRequest(ctx, "PUT", path, nil, body, false)
+	return err
+}
+
+// GetSearchTemplate returns the search template stored under id. A missing
+// template is returned as *Error with status 404.
+func (this *Client) GetSearchTemplate(ctx context.Context, id string) (*StoredScript, error) {
+	path, err := uritemplates.Expand("/_scripts/{id}", map[string]string{
+		"id": id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	response, err := this.httpRequest(ctx, "GET", path, nil, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := struct {
+		Script *StoredScript `json:"script"`
+	}{}
+	if err := json.Unmarshal(response.Body, &ret); err != nil {
+		return nil, err
+	}
+	if ret.Script == nil {
+		return nil, fmt.Errorf("search template %s has no script", id)
+	}
+	return ret.Script, nil
+}
+
+// DeleteSearchTemplate deletes the search template stored under id.
+func (this *Client) DeleteSearchTemplate(ctx context.Context, id string) error {
+	path, err := uritemplates.Expand("/_scripts/{id}", map[string]string{
+		"id": id,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = this.httpRequest(ctx, "DELETE", path, nil, nil, false)
+	return err
+}
+
+// SaveAsTemplate stores the body of the query, as built by QueryBuilder.Build,
+// as a search template under id. String values like "{{flag}}" become
+// template parameters.
+func (this *Query) SaveAsTemplate(ctx context.Context, id string) error {
+	builder := QueryBuilder{}
+	body, err := builder.Build(this)
+	if err != nil {
+		return err
+	}
+	return this.client.PutSearchTemplate(ctx, id, body)
+}
+
+// SearchTemplateService executes a stored or inline search template with the
+// _search/template endpoint.
+//
+//	result, err := client.SearchTemplate("md_fin_waybill").
+//		Id("waybill_by_flag").
+//		Param("flag", "1").
+//		Do(ctx)
+type SearchTemplateService struct {
+	client  *Client
+	index   []string
+	id      string
+	source  interface{}
+	params  map[string]interface{}
+	explain bool
+	profile bool
+	options map[string]string
+}
+
+func NewSearchTemplateService(c *Client) *SearchTemplateService {
+	return &SearchTemplateService{
+		client:  c,
+		index:   make([]string, 0),
+		params:  make(map[string]interface{}),
+		options: make(map[string]string),
+	}
+}
+
+func (this *Client) SearchTemplate(index ...string) *SearchTemplateService {
+	return NewSearchTemplateService(this).Index(index...)
+}
+
+func (this *SearchTemplateService) Index(index ...string) *SearchTemplateService {
+	this.index = append(this.index, index...)
+	return this
+}
+
+// Id sets the id of the stored template to execute.
+func (this *SearchTemplateService) Id(id string) *SearchTemplateService {
+	this.id = id
+	return this
+}
+
+// Source sets an inline template, as a string or a search body with
+// placeholders, in place of a stored one.
+func (this *SearchTemplateService) Source(source interface{}) *SearchTemplateService {
+	this.source = source
+	return this
+}
+
+// Params sets the parameters of the template.
+func (this *SearchTemplateService) Params(params map[string]interface{}) *SearchTemplateService {
+	for name, value := range params {
+		this.params[name] = value
+	}
+	return this
+}
+
+func (this *SearchTemplateService) Param(name string, value interface{}) *SearchTemplateService {
+	this.params[name] = value
+	return this
+}
+
+func (this *SearchTemplateService) Explain(explain bool) *SearchTemplateService {
+	this.explain = explain
+	return this
+}
+
+func (this *SearchTemplateService) Profile(profile bool) *SearchTemplateService {
+	this.profile = profile
+	return this
+}
+
+// Options sets a query string option, e.g. "routing" or "preference".
+func (this *SearchTemplateService) Options(name, options string) *SearchTemplateService {
+	this.options[name] = options
+	return this
+}
+
+// Body returns the body of the request: the template id or source and its
+// parameters.
+func (this *SearchTemplateService) Body() (map[string]interface{}, error) {
+	body := make(map[string]interface{})
+	if this.id != "" {
+		body["id"] = this.id
+	} else if this.source != nil {
+		body["source"] = this.source
+	} else {
+		return nil, fmt.Errorf("search template requires an id or a source")
+	}
+	if len(this.params) > 0 {
+		body["params"] = this.params
+	}
+	if this.explain {
+		body["explain"] = true
+	}
+	if this.profile {
+		body["profile"] = true
+	}
+	return body, nil
+}
+
+func (this *SearchTemplateService) buildUrl() (string, url.Values, error) {
+	var (
+		err    error
+		path   = "/_search/template"
+		params = url.Values{}
+	)
+	if len(this.index) > 0 {
+		path, err = uritemplates.Expand("/{index}/_search/template", map[string]string{
+			"index": strings.Join(this.index, ","),
+		})
+		if err != nil {
+			return "", url.Values{}, err
+		}
+	}
+	for k, v := range this.options {
+		params.Add(k, v)
+	}
+	return path, params, nil
+}
+
+// Do executes the template. A non-2xx response is returned as *Error.
+func (this *SearchTemplateService) Do(ctx context.Context) (*SearchResult, error) {
+	path, params, err := this.buildUrl()
+	if err != nil {
+		return nil, err
+	}
+	body, err := this.Body()
+	if err != nil {
+		return nil, err
+	}
+	response, err := this.client.httpRequest(ctx, "POST", path, params, body, false)
+	if err != nil {
+		return nil, err
+	}
+	return newSearchResult(response)
+}
+
+// Render renders the template with its parameters, without executing it,
+// and returns the resulting search body.
+func (this *SearchTemplateService) Render(ctx context.Context) (json.RawMessage, error) {
+	body, err := this.Body()
+	if err != nil {
+		return nil, err
+	}
+	delete(body, "explain")
+	delete(body, "profile")
+	response, err := this.client.httpRequest(ctx, "POST", "/_render/template", nil, body, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := struct {
+		TemplateOutput json.RawMessage `json:"template_output"`
+	}{}
+	if err := json.Unmarshal(response.Body, &ret); err != nil {
+		return nil, err
+	}
+	return ret.TemplateOutput, nil
+}
+
+// MultiSearchTemplateService executes several search templates in a single
+// round trip with the _msearch/template endpoint.
+type MultiSearchTemplateService struct {
+	client                *Client
+	templates             []*SearchTemplateService
+	maxConcurrentSearches int
+}
+
+func NewMultiSearchTemplateService(c *Client) *MultiSearchTemplateService {
+	return &MultiSearchTemplateService{
+		client:    c,
+		templates: make([]*SearchTemplateService, 0),
+	}
+}
+
+func (this *Client) MultiSearchTemplate() *MultiSearchTemplateService {
+	return NewMultiSearchTemplateService(this)
+}
+
+// Add adds search templates, which are executed and returned in order.
+func (this *MultiSearchTemplateService) Add(templates ...*SearchTemplateService) *MultiSearchTemplateService {
+	this.templates = append(this.templates, templates...)
+	return this
+}
+
+// MaxConcurrentSearches limits the number of searches executed concurrently
+// by Elasticsearch.
+func (this *MultiSearchTemplateService) MaxConcurrentSearches(max int) *MultiSearchTemplateService {
+	this.maxConcurrentSearches = max
+	return this
+}
+
+// Body returns the NDJSON body: a header line with the indices and options
+// of each template, followed by its body. Options that do not belong in the
+// header are ignored, as the body of a template has no such parameters.
+func (this *MultiSearchTemplateService) Body() (NDJSON, error) {
+	lines := make(NDJSON, 0, 2*len(this.templates))
+	for i, template := range this.templates {
+		header := make(map[string]interface{})
+		if len(template.index) > 0 {
+			header["index"] = template.index
+		}
+		for name, value := range template.options {
+			if multiSearchHeaderOptions[name] {
+				header[name] = value
+			}
+		}
+		body, err := template.Body()
+		if err != nil {
+			return nil, fmt.Errorf("search template %d: %v", i, err)
+		}
+		lines = append(lines, header, body)
+	}
+	return lines, nil
+}
+
+func (this *MultiSearchTemplateService) Do(ctx context.Context) (*MultiSearchResult, error) {
+	body, err := this.Body()
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	if this.maxConcurrentSearches > 0 {
+		params.Set("max_concurrent_searches", strconv.Itoa(this.maxConcurrentSearches))
+	}
+	response, err := this.client.httpRequest(ctx, "POST", "/_msearch/template", params, body, false)
+	if err != nil {
+		return nil, err
+	}
+	result := new(MultiSearchResult)
+	decoder := json.NewDecoder(bytes.NewReader(response.Body))
+	decoder.UseNumber()
+	if err := decoder.Decode(result); err != nil {
+		return nil, err
+	}
+	if len(result.Responses) != len(this.templates) {
+		return nil, fmt.Errorf("multi search template returned %d responses for %d templates", len(result.Responses), len(this.templates))
+	}
+	return result, nil
+}
@@ -0,0 +1,91 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestQuerySaveAsTemplate(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/_scripts/waybill_by_flag" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		expected := `{"script":{"lang":"mustache","source":{"aggregations":{},"query":{"bool":{"must":[{"range":{"F_FJScan_Flag":{"lt":"{{flag}}"}}}]}},"size":10}}}`
+		if string(body) != expected {
+			t.Errorf("expected body\n%s\ngot\n%s", expected, string(body))
+		}
+		w.Write([]byte(`{"acknowledged": true}`))
+	})
+	err := client.Search("md_fin_waybill").AndWhere("<", "F_FJScan_Flag", "{{flag}}").SaveAsTemplate(context.Background(), "waybill_by_flag")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSearchTemplate(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/md_fin_waybill/_search/template" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		expected := `{"id":"waybill_by_flag","params":{"flag":"1"}}`
+		if string(body) != expected {
+			t.Errorf("expected body %s, got %s", expected, string(body))
+		}
+		w.Write([]byte(`{"took": 2, "hits": {"total": {"value": 1, "relation": "eq"}, "hits": [{"_id": "1"}]}}`))
+	})
+	result, err := client.SearchTemplate("md_fin_waybill").Id("waybill_by_flag").Param("flag", "1").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TotalHits() != 1 {
+		t.Errorf("expected 1 hit, got %d", result.TotalHits())
+	}
+
+	if _, err := client.SearchTemplate("md_fin_waybill").Do(context.Background()); err == nil {
+		t.Error("expected error for a template without id or source")
+	}
+}
+
+func TestMultiSearchTemplate(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_msearch/template" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		expected := `{"index":["md_fin_waybill"]}` + "\n" +
+			`{"id":"waybill_by_flag","params":{"flag":"1"}}` + "\n" +
+			`{"preference":"_local"}` + "\n" +
+			`{"source":"{\"query\":{\"match_all\":{}}}"}` + "\n"
+		if string(body) != expected {
+			t.Errorf("expected body\n%s\ngot\n%s", expected, string(body))
+		}
+		w.Write([]byte(`{"took": 5, "responses": [{"hits": {"hits": []}, "status": 200}, {"hits": {"hits": []}, "status": 200}]}`))
+	})
+	result, err := client.MultiSearchTemplate().Add(
+		client.SearchTemplate("md_fin_waybill").Id("waybill_by_flag").Param("flag", "1"),
+		client.SearchTemplate().Source(`{"query":{"match_all":{}}}`).Options("preference", "_local"),
+	).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Responses) != 2 {
+		t.Errorf("expected 2 responses, got %d", len(result.Responses))
+	}
+}
+
+func TestMultiSearchTemplateResponseCount(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"took": 5, "responses": [{"took": 2, "hits": {"hits": []}, "status": 200}]}`))
+	})
+	_, err := client.MultiSearchTemplate().Add(
+		client.SearchTemplate("md_fin_waybill").Id("waybill_by_flag"),
+		client.SearchTemplate("md_fin_customer").Id("customer_by_name"),
+	).Do(context.Background())
+	if err == nil {
+		t.Error("expected error for a missing response")
+	}
+}
@@ -0,0 +1,191 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"sync"
+)
+
+// SlicedExport consumes all hits of a query in parallel by splitting it into
+// slices, each scrolled (or paged with search_after on a shared point in
+// time) by a pool of workers. The hits of all slices are merged into a
+// single sink.
+//
+//	export := NewSlicedExport(client.Search("md_fin_waybill").Limit(1000)).Slices(8).Workers(4)
+//	err := export.Each(ctx, func(hit *SearchHit) error { ... })
+type SlicedExport struct {
+	query     *Query
+	slices    int
+	workers   int
+	keepAlive string
+	pit       bool
+}
+
+func NewSlicedExport(query *Query) *SlicedExport {
+	return &SlicedExport{
+		query:     query,
+		slices:    2,
+		keepAlive: "1m",
+	}
+}
+
+// Slices sets the number of slices the query is split into. The default is 2.
+func (this *SlicedExport) Slices(slices int) *SlicedExport {
+	this.slices = slices
+	return this
+}
+
+// Workers sets the maximum number of slices consumed concurrently. It
+// defaults to the number of slices.
+func (this *SlicedExport) Workers(workers int) *SlicedExport {
+	this.workers = workers
+	return this
+}
+
+// KeepAlive sets how long the scroll contexts, or the point in time, are
+// kept alive between two pages. The default is "1m".
+func (this *SlicedExport) KeepAlive(keepAlive string) *SlicedExport {
+	this.keepAlive = keepAlive
+	return this
+}
+
+// PointInTime pages through the slices with search_after on a single point
+// in time instead of scrolling.
+func (this *SlicedExport) PointInTime(pit bool) *SlicedExport {
+	this.pit = pit
+	return this
+}
+
+// Each calls fn for every hit of every slice. fn is never called
+// concurrently. On the first error returned by fn or by a slice, all
+// remaining slices are stopped and their scroll contexts cleared before
+// Each returns that error.
+func (this *SlicedExport) Each(ctx context.Context, fn func(hit *SearchHit) error) error {
+	slices := this.slices
+	if slices < 1 {
+		slices = 1
+	}
+	workers := this.workers
+	if workers < 1 || workers > slices {
+		workers = slices
+	}
+
+	pitId := ""
+	if this.pit {
+		id, err := this.query.client.OpenPointInTime(ctx, this.keepAlive, this.query.index...)
+		if err != nil {
+			return err
+		}
+		pitId = id
+	}
+
+	exportCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		ids      = make(chan int)
+		hits     = make(chan *SearchHit, workers)
+	)
+	fail := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		mu.Unlock()
+		cancel()
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range ids {
+				if err := this.exportSlice(exportCtx, id, slices, pitId, hits); err != nil {
+					fail(err)
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		defer close(ids)
+		for id := 0; id < slices; id++ {
+			select {
+			case ids <- id:
+			case <-exportCtx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(hits)
+	}()
+
+	for hit := range hits {
+		if exportCtx.Err() != nil {
+			// shutting down: drain the hits until all workers are done
+			continue
+		}
+		if err := fn(hit); err != nil {
+			fail(err)
+		}
+	}
+
+	if pitId != "" {
+		// ctx may be done already, so close with a fresh context
+		if err := this.query.client.ClosePointInTime(context.Background(), pitId); err != nil {
+			fail(err)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return firstErr
+}
+
+// Stream runs the export in the background, sending all hits to the
+// returned hits channel. Once the export is done the hits channel is closed
+// and the error, if any, is sent on the error channel. The hits channel must
+// be drained, or ctx cancelled, for the export to finish.
+func (this *SlicedExport) Stream(ctx context.Context) (<-chan *SearchHit, <-chan error) {
+	var (
+		hits = make(chan *SearchHit)
+		errc = make(chan error, 1)
+	)
+	go func() {
+		err := this.Each(ctx, func(hit *SearchHit) error {
+			select {
+			case hits <- hit:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		close(hits)
+		errc <- err
+		close(errc)
+	}()
+	return hits, errc
+}
+
+func (this *SlicedExport) exportSlice(ctx context.Context, id, max int, pitId string, hits chan<- *SearchHit) error {
+	query := this.query.clone()
+	if max > 1 {
+		query.Slice(id, max)
+	}
+	send := func(hit *SearchHit) error {
+		select {
+		case hits <- hit:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if pitId != "" {
+		return NewSearchAfterPaginator(query).PointInTime(pitId).KeepAlive(this.keepAlive).Each(ctx, send)
+	}
+	return NewScrollService(query.client).Query(query).KeepAlive(this.keepAlive).Each(ctx, send)
+}
@@ -0,0 +1,77 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestSlicedExport(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		served  = make(map[string]bool)
+		cleared = 0
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "DELETE" {
+			cleared++
+			return
+		}
+		body := struct {
+			ScrollId string         `json:"scroll_id"`
+			Slice    map[string]int `json:"slice"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.ScrollId != "" {
+			// every slice has a single page
+			fmt.Fprintf(w, `{"_scroll_id": %q, "hits": {"hits": []}}`, body.ScrollId)
+			return
+		}
+		if body.Slice["max"] != 3 {
+			t.Errorf("expected 3 slices, got %v", body.Slice)
+		}
+		id := fmt.Sprint(body.Slice["id"])
+		served[id] = true
+		fmt.Fprintf(w, `{"_scroll_id": "scroll-%s", "hits": {"hits": [{"_id": "%s-1"}, {"_id": "%s-2"}]}}`, id, id, id)
+	})
+
+	ids := make(map[string]bool)
+	err := NewSlicedExport(client.Search("md_fin_waybill")).Slices(3).Workers(2).Each(context.Background(), func(hit *SearchHit) error {
+		ids[hit.ID] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 6 {
+		t.Errorf("expected 6 hits, got %v", ids)
+	}
+	if len(served) != 3 {
+		t.Errorf("expected 3 slices to be served, got %v", served)
+	}
+	if cleared != 3 {
+		t.Errorf("expected 3 scroll contexts to be cleared, got %d", cleared)
+	}
+}
+
+func TestSlicedExportError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			return
+		}
+		w.Write([]byte(`{"_scroll_id": "abc", "hits": {"hits": [{"_id": "1"}]}}`))
+	})
+	stop := errors.New("stop")
+	err := NewSlicedExport(client.Search("md_fin_waybill")).Slices(4).Each(context.Background(), func(hit *SearchHit) error {
+		return stop
+	})
+	if err != stop {
+		t.Errorf("expected %v, got %v", stop, err)
+	}
+}
@@ -0,0 +1,356 @@
+package go_elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Suggester is a typed suggester, added to a search with Query.Suggest.
+// Its suggestions are returned in SearchResult.Suggest under the same name.
+type Suggester interface {
+	Source() (interface{}, error)
+}
+
+// TermSuggester suggests corrections of the individual terms of a text.
+//
+//	client.Search("md_fin_customer").Limit(0).Suggest("did_you_mean",
+//		NewTermSuggester("F_O_CustomerName").Text("acmee").SuggestMode("popular"))
+type TermSuggester struct {
+	field         string
+	text          string
+	analyzer      string
+	size          int
+	sort          string
+	suggestMode   string
+	maxEdits      int
+	prefixLength  *int
+	minWordLength int
+}
+
+func NewTermSuggester(field string) *TermSuggester {
+	return &TermSuggester{
+		field: field,
+	}
+}
+
+// Text sets the text to make suggestions for.
+func (this *TermSuggester) Text(text string) *TermSuggester {
+	this.text = text
+	return this
+}
+
+func (this *TermSuggester) Analyzer(analyzer string) *TermSuggester {
+	this.analyzer = analyzer
+	return this
+}
+
+// Size sets the maximum number of suggestions per term.
+func (this *TermSuggester) Size(size int) *TermSuggester {
+	this.size = size
+	return this
+}
+
+// Sort sets how suggestions are sorted: "score" or "frequency".
+func (this *TermSuggester) Sort(sort string) *TermSuggester {
+	this.sort = sort
+	return this
+}
+
+// SuggestMode sets which terms get suggestions: "missing", "popular" or
+// "always".
+func (this *TermSuggester) SuggestMode(suggestMode string) *TermSuggester {
+	this.suggestMode = suggestMode
+	return this
+}
+
+// MaxEdits sets the maximum edit distance of suggestions, 1 or 2.
+func (this *TermSuggester) MaxEdits(maxEdits int) *TermSuggester {
+	this.maxEdits = maxEdits
+	return this
+}
+
+// PrefixLength sets the number of leading characters that must match.
+func (this *TermSuggester) PrefixLength(prefixLength int) *TermSuggester {
+	this.prefixLength = &prefixLength
+	return this
+}
+
+func (this *TermSuggester) MinWordLength(minWordLength int) *TermSuggester {
+	this.minWordLength = minWordLength
+	return this
+}
+
+func (this *TermSuggester) Source() (interface{}, error) {
+	if this.field == "" {
+		return nil, fmt.Errorf("term suggester requires a field")
+	}
+	options := map[string]interface{}{"field": this.field}
+	if this.analyzer != "" {
+		options["analyzer"] = this.analyzer
+	}
+	if this.size > 0 {
+		options["size"] = this.size
+	}
+	if this.sort != "" {
+		options["sort"] = this.sort
+	}
+	if this.suggestMode != "" {
+		options["suggest_mode"] = this.suggestMode
+	}
+	if this.maxEdits > 0 {
+		options["max_edits"] = this.maxEdits
+	}
+	if this.prefixLength != nil {
+		options["prefix_length"] = *this.prefixLength
+	}
+	if this.minWordLength > 0 {
+		options["min_word_length"] = this.minWordLength
+	}
+	source := map[string]interface{}{"term": options}
+	if this.text != "" {
+		source["text"] = this.text
+	}
+	return source, nil
+}
+
+// PhraseSuggester suggests corrections of a whole phrase, e.g. for
+// "did you mean" prompts.
+type PhraseSuggester struct {
+	field                   string
+	text                    string
+	analyzer                string
+	size                    int
+	gramSize                int
+	confidence              *float64
+	maxErrors               *float64
+	realWordErrorLikelihood *float64
+	preTag                  string
+	postTag                 string
+	directGenerators        []map[string]interface{}
+}
+
+func NewPhraseSuggester(field string) *PhraseSuggester {
+	return &PhraseSuggester{
+		field:            field,
+		directGenerators: make([]map[string]interface{}, 0),
+	}
+}
+
+// Text sets the text to make suggestions for.
+func (this *PhraseSuggester) Text(text string) *PhraseSuggester {
+	this.text = text
+	return this
+}
+
+func (this *PhraseSuggester) Analyzer(analyzer string) *PhraseSuggester {
+	this.analyzer = analyzer
+	return this
+}
+
+// Size sets the maximum number of suggested phrases.
+func (this *PhraseSuggester) Size(size int) *PhraseSuggester {
+	this.size = size
+	return this
+}
+
+// GramSize sets the maximum size of the n-grams in the field.
+func (this *PhraseSuggester) GramSize(gramSize int) *PhraseSuggester {
+	this.gramSize = gramSize
+	return this
+}
+
+// Confidence sets the factor the score of the input phrase is multiplied by
+// to get the threshold for other suggestions.
+func (this *PhraseSuggester) Confidence(confidence float64) *PhraseSuggester {
+	this.confidence = &confidence
+	return this
+}
+
+// MaxErrors sets the maximum number (>= 1) or percentage (< 1) of terms
+// considered misspelled.
+func (this *PhraseSuggester) MaxErrors(maxErrors float64) *PhraseSuggester {
+	this.maxErrors = &maxErrors
+	return this
+}
+
+func (this *PhraseSuggester) RealWordErrorLikelihood(likelihood float64) *PhraseSuggester {
+	this.realWordErrorLikelihood = &likelihood
+	return this
+}
+
+// Highlight wraps the changed terms of the suggestions in the given tags.
+func (this *PhraseSuggester) Highlight(preTag, postTag string) *PhraseSuggester {
+	this.preTag = preTag
+	this.postTag = postTag
+	return this
+}
+
+// DirectGenerator adds a candidate generator working on the given field,
+// with suggestMode "missing", "popular" or "always".
+func (this *PhraseSuggester) DirectGenerator(field, suggestMode string) *PhraseSuggester {
+	generator := map[string]interface{}{"field": field}
+	if suggestMode != "" {
+		generator["suggest_mode"] = suggestMode
+	}
+	this.directGenerators = append(this.directGenerators, generator)
+	return this
+}
+
+func (this *PhraseSuggester) Source() (interface{}, error) {
+	if this.field == "" {
+		return nil, fmt.Errorf("phrase suggester requires a field")
+	}
+	options := map[string]interface{}{"field": this.field}
+	if this.analyzer != "" {
+		options["analyzer"] = this.analyzer
+	}
+	if this.size > 0 {
+		options["size"] = this.size
+	}
+	if this.gramSize > 0 {
+		options["gram_size"] = this.gramSize
+	}
+	if this.confidence != nil {
+		options["confidence"] = *this.confidence
+	}
+	if this.maxErrors != nil {
+		options["max_errors"] = *this.maxErrors
+	}
+	if this.realWordErrorLikelihood != nil {
+		options["real_word_error_likelihood"] = *this.realWordErrorLikelihood
+	}
+	if this.preTag != "" || this.postTag != "" {
+		options["highlight"] = map[string]string{"pre_tag": this.preTag, "post_tag": this.postTag}
+	}
+	if len(this.directGenerators) > 0 {
+		options["direct_generator"] = this.directGenerators
+	}
+	source := map[string]interface{}{"phrase": options}
+	if this.text != "" {
+		source["text"] = this.text
+	}
+	return source, nil
+}
+
+// CompletionSuggester suggests completions of a prefix from a field mapped
+// as "completion", e.g. for autocomplete.
+//
+//	client.Search("md_fin_customer").Limit(0).Suggest("customers",
+//		NewCompletionSuggester("F_O_CustomerName_suggest").Prefix("ac").Size(5).SkipDuplicates(true))
+type CompletionSuggester struct {
+	field          string
+	prefix         string
+	regex          string
+	size           int
+	skipDuplicates bool
+	fuzziness      interface{}
+	contexts       map[string]interface{}
+}
+
+func NewCompletionSuggester(field string) *CompletionSuggester {
+	return &CompletionSuggester{
+		field: field,
+	}
+}
+
+// Prefix sets the prefix to complete.
+func (this *CompletionSuggester) Prefix(prefix string) *CompletionSuggester {
+	this.prefix = prefix
+	return this
+}
+
+// Regex completes the matches of a regular expression instead of a prefix.
+func (this *CompletionSuggester) Regex(regex string) *CompletionSuggester {
+	this.regex = regex
+	return this
+}
+
+func (this *CompletionSuggester) Size(size int) *CompletionSuggester {
+	this.size = size
+	return this
+}
+
+func (this *CompletionSuggester) SkipDuplicates(skipDuplicates bool) *CompletionSuggester {
+	this.skipDuplicates = skipDuplicates
+	return this
+}
+
+// Fuzziness tolerates typos in the prefix, e.g. 1, 2 or "AUTO".
+func (this *CompletionSuggester) Fuzziness(fuzziness interface{}) *CompletionSuggester {
+	this.fuzziness = fuzziness
+	return this
+}
+
+// Context filters the completions by the values of a context of the field.
+func (this *CompletionSuggester) Context(name string, values ...interface{}) *CompletionSuggester {
+	if this.contexts == nil {
+		this.contexts = make(map[string]interface{})
+	}
+	this.contexts[name] = values
+	return this
+}
+
+func (this *CompletionSuggester) Source() (interface{}, error) {
+	if this.field == "" {
+		return nil, fmt.Errorf("completion suggester requires a field")
+	}
+	options := map[string]interface{}{"field": this.field}
+	if this.size > 0 {
+		options["size"] = this.size
+	}
+	if this.skipDuplicates {
+		options["skip_duplicates"] = true
+	}
+	if this.fuzziness != nil {
+		options["fuzzy"] = map[string]interface{}{"fuzziness": this.fuzziness}
+	}
+	if len(this.contexts) > 0 {
+		options["contexts"] = this.contexts
+	}
+	source := map[string]interface{}{"completion": options}
+	if this.regex != "" {
+		source["regex"] = this.regex
+	} else {
+		source["prefix"] = this.prefix
+	}
+	return source, nil
+}
+
+// SearchSuggestion is the suggestion for a term or phrase of the text.
+type SearchSuggestion struct {
+	Text    string                   `json:"text"`
+	Offset  int                      `json:"offset"`
+	Length  int                      `json:"length"`
+	Options []SearchSuggestionOption `json:"options"`
+}
+
+// SearchSuggestionOption is a single suggestion. The document fields are
+// only set by the completion suggester.
+type SearchSuggestionOption struct {
+	Text         string              `json:"text"`
+	Score        float64             `json:"score"`
+	Freq         int64               `json:"freq,omitempty"`
+	Highlighted  string              `json:"highlighted,omitempty"`
+	CollateMatch bool                `json:"collate_match,omitempty"`
+	Index        string              `json:"_index,omitempty"`
+	ID           string              `json:"_id,omitempty"`
+	Source       *json.RawMessage    `json:"_source,omitempty"`
+	Contexts     map[string][]string `json:"contexts,omitempty"`
+}
+
+// UnmarshalJSON also accepts the "_score" returned by the completion
+// suggester in place of "score".
+func (this *SearchSuggestionOption) UnmarshalJSON(data []byte) error {
+	type option SearchSuggestionOption
+	aux := struct {
+		*option
+		DocScore *float64 `json:"_score"`
+	}{option: (*option)(this)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.DocScore != nil {
+		this.Score = *aux.DocScore
+	}
+	return nil
+}
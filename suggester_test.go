@@ -0,0 +1,41 @@
+package go_elasticsearch
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	client, _ := NewClient()
+	query := client.Search("md_fin_customer").Limit(0).
+		Suggest("did_you_mean", NewTermSuggester("F_O_CustomerName").Text("acmee").SuggestMode("popular")).
+		Suggest("customers", NewCompletionSuggester("F_O_CustomerName_suggest").Prefix("ac").Size(5))
+	builder := QueryBuilder{}
+	body, err := builder.Build(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(body["suggest"])
+	expected := `{"customers":{"completion":{"field":"F_O_CustomerName_suggest","size":5},"prefix":"ac"},` +
+		`"did_you_mean":{"term":{"field":"F_O_CustomerName","suggest_mode":"popular"},"text":"acmee"}}`
+	if string(data) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, string(data))
+	}
+
+	result := new(SearchResult)
+	err = json.Unmarshal([]byte(`{"suggest": {
+		"did_you_mean": [{"text": "acmee", "offset": 0, "length": 5, "options": [{"text": "acme", "score": 0.75, "freq": 12}]}],
+		"customers": [{"text": "ac", "offset": 0, "length": 2, "options": [{"text": "Acme Freight", "_index": "md_fin_customer", "_id": "7", "_score": 3, "_source": {}}]}]
+	}}`), result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	term := result.Suggest["did_you_mean"][0].Options[0]
+	if term.Text != "acme" || term.Score != 0.75 || term.Freq != 12 {
+		t.Errorf("unexpected term suggestion %+v", term)
+	}
+	completion := result.Suggest["customers"][0].Options[0]
+	if completion.Text != "Acme Freight" || completion.ID != "7" || completion.Score != 3 {
+		t.Errorf("unexpected completion suggestion %+v", completion)
+	}
+}
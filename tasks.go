@@ -0,0 +1,241 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/wh5231/go-elasticsearch/uritemplates"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TaskInfo describes a running or completed task, e.g. a reindex started
+// with WaitForCompletion(false).
+type TaskInfo struct {
+	Node               string            `json:"node"`
+	Id                 int64             `json:"id"`
+	Type               string            `json:"type"`
+	Action             string            `json:"action"`
+	Description        string            `json:"description,omitempty"`
+	Status             *TaskStatus       `json:"status,omitempty"`
+	StartTimeInMillis  int64             `json:"start_time_in_millis"`
+	RunningTimeInNanos int64             `json:"running_time_in_nanos"`
+	Cancellable        bool              `json:"cancellable"`
+	Cancelled          bool              `json:"cancelled,omitempty"`
+	ParentTaskId       string            `json:"parent_task_id,omitempty"`
+	Headers            map[string]string `json:"headers,omitempty"`
+}
+
+// TaskId returns the id of the task as "node:id".
+func (this *TaskInfo) TaskId() string {
+	return fmt.Sprintf("%s:%d", this.Node, this.Id)
+}
+
+// TaskStatus is the progress of a reindex, update by query or delete by
+// query task.
+type TaskStatus struct {
+	Total                int64                    `json:"total"`
+	Created              int64                    `json:"created"`
+	Updated              int64                    `json:"updated"`
+	Deleted              int64                    `json:"deleted"`
+	Batches              int64                    `json:"batches"`
+	VersionConflicts     int64                    `json:"version_conflicts"`
+	Noops                int64                    `json:"noops"`
+	Retries              BulkIndexByScrollRetries `json:"retries"`
+	ThrottledMillis      int64                    `json:"throttled_millis"`
+	RequestsPerSecond    float64                  `json:"requests_per_second"`
+	ThrottledUntilMillis int64                    `json:"throttled_until_millis"`
+}
+
+// GetTaskResult is the response of GetTask. Response is set once the task
+// completed successfully, Error if it failed.
+type GetTaskResult struct {
+	Completed bool                       `json:"completed"`
+	Task      *TaskInfo                  `json:"task"`
+	Response  *BulkIndexByScrollResponse `json:"response,omitempty"`
+	Error     *ErrorDetails              `json:"error,omitempty"`
+}
+
+// GetTask returns the task with the given id, "node:id", e.g. as returned in
+// BulkIndexByScrollResponse.Task.
+func (this *Client) GetTask(ctx context.Context, id string) (*GetTaskResult, error) {
+	path, err := uritemplates.Expand("/_tasks/{id}", map[string]string{
+		"id": id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	response, err := this.httpRequest(ctx, "GET", path, nil, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(GetTaskResult)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// WaitForTask polls the task with the given id every interval until it
+// completed, and returns its result. A task that failed is returned along
+// with its error as *Error; the failures of single documents are in
+// GetTaskResult.Response.Failures. An interval of 0 or less polls every
+// second.
+func (this *Client) WaitForTask(ctx context.Context, id string, interval time.Duration) (*GetTaskResult, error) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		result, err := this.GetTask(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if result.Completed {
+			if result.Error != nil {
+				return result, &Error{Status: 500, Details: result.Error}
+			}
+			return result, nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return result, ctx.Err()
+		}
+	}
+}
+
+// CancelTask cancels the task with the given id. Only cancellable tasks,
+// like reindex, update by query and delete by query, can be canceled.
+func (this *Client) CancelTask(ctx context.Context, id string) error {
+	path, err := uritemplates.Expand("/_tasks/{id}/_cancel", map[string]string{
+		"id": id,
+	})
+	if err != nil {
+		return err
+	}
+	response, err := this.httpRequest(ctx, "POST", path, nil, nil, false)
+	if err != nil {
+		return err
+	}
+	ret := new(ListTasksResult)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return err
+	}
+	if len(ret.NodeFailures) > 0 {
+		return &Error{Status: 500, Details: ret.NodeFailures[0]}
+	}
+	if len(ret.TaskFailures) > 0 {
+		return &Error{Status: 500, Details: ret.TaskFailures[0].Reason}
+	}
+	return nil
+}
+
+// ListTasksService lists the running tasks.
+//
+//	result, err := client.ListTasks().Actions("*reindex", "*byquery").Detailed(true).Do(ctx)
+type ListTasksService struct {
+	client       *Client
+	actions      []string
+	nodes        []string
+	parentTaskId string
+	detailed     bool
+}
+
+func NewListTasksService(c *Client) *ListTasksService {
+	return &ListTasksService{
+		client: c,
+	}
+}
+
+func (this *Client) ListTasks() *ListTasksService {
+	return NewListTasksService(this)
+}
+
+// Actions filters the tasks by action, e.g. "*reindex".
+func (this *ListTasksService) Actions(actions ...string) *ListTasksService {
+	this.actions = append(this.actions, actions...)
+	return this
+}
+
+func (this *ListTasksService) Nodes(nodes ...string) *ListTasksService {
+	this.nodes = append(this.nodes, nodes...)
+	return this
+}
+
+func (this *ListTasksService) ParentTaskId(parentTaskId string) *ListTasksService {
+	this.parentTaskId = parentTaskId
+	return this
+}
+
+// Detailed returns the status and description of the tasks.
+func (this *ListTasksService) Detailed(detailed bool) *ListTasksService {
+	this.detailed = detailed
+	return this
+}
+
+func (this *ListTasksService) Do(ctx context.Context) (*ListTasksResult, error) {
+	params := url.Values{}
+	if len(this.actions) > 0 {
+		params.Set("actions", strings.Join(this.actions, ","))
+	}
+	if len(this.nodes) > 0 {
+		params.Set("nodes", strings.Join(this.nodes, ","))
+	}
+	if this.parentTaskId != "" {
+		params.Set("parent_task_id", this.parentTaskId)
+	}
+	if this.detailed {
+		params.Set("detailed", strconv.FormatBool(this.detailed))
+	}
+	response, err := this.client.httpRequest(ctx, "GET", "/_tasks", params, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(ListTasksResult)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ListTasksResult holds the tasks per node.
+type ListTasksResult struct {
+	Nodes        map[string]*TaskNode `json:"nodes"`
+	NodeFailures []*ErrorDetails      `json:"node_failures,omitempty"`
+	TaskFailures []*TaskFailure       `json:"task_failures,omitempty"`
+}
+
+// TaskFailure describes why an operation on a task failed, e.g. canceling a
+// task that is not cancellable.
+type TaskFailure struct {
+	TaskId int64         `json:"task_id"`
+	NodeId string        `json:"node_id"`
+	Status string        `json:"status"`
+	Reason *ErrorDetails `json:"reason"`
+}
+
+// TaskNode is a node and its tasks by task id.
+type TaskNode struct {
+	Name  string               `json:"name"`
+	Host  string               `json:"host"`
+	Tasks map[string]*TaskInfo `json:"tasks"`
+}
+
+// Tasks returns the tasks of all nodes, oldest first.
+func (this *ListTasksResult) Tasks() []*TaskInfo {
+	tasks := make([]*TaskInfo, 0)
+	for _, node := range this.Nodes {
+		for _, task := range node.Tasks {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].StartTimeInMillis < tasks[j].StartTimeInMillis
+	})
+	return tasks
+}
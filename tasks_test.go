@@ -0,0 +1,84 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitForTask(t *testing.T) {
+	var polls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_tasks/oTUltX4IQMOUUVeiohTt8A:12345" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if atomic.AddInt32(&polls, 1) < 3 {
+			w.Write([]byte(`{"completed": false, "task": {"node": "oTUltX4IQMOUUVeiohTt8A", "id": 12345, "action": "indices:data/write/reindex",
+				"status": {"total": 100, "created": 40}}}`))
+			return
+		}
+		w.Write([]byte(`{"completed": true, "task": {"node": "oTUltX4IQMOUUVeiohTt8A", "id": 12345, "action": "indices:data/write/reindex",
+			"status": {"total": 100, "created": 100}},
+			"response": {"took": 1200, "total": 100, "created": 99, "failures": [{"index": "md_fin_waybill_v2", "id": "W1", "status": 400,
+				"cause": {"type": "mapper_parsing_exception", "reason": "failed to parse field [F_OrderTime]"}}]}}`))
+	})
+	result, err := client.WaitForTask(context.Background(), "oTUltX4IQMOUUVeiohTt8A:12345", time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if polls != 3 {
+		t.Errorf("expected 3 polls, got %d", polls)
+	}
+	if result.Task.TaskId() != "oTUltX4IQMOUUVeiohTt8A:12345" || result.Task.Status.Created != 100 {
+		t.Errorf("unexpected task %+v", result.Task)
+	}
+	if result.Response == nil || result.Response.Created != 99 || len(result.Response.Failures) != 1 {
+		t.Errorf("unexpected response %+v", result.Response)
+	}
+}
+
+func TestWaitForTaskWithoutInterval(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"completed": true, "task": {"node": "oTUltX4IQMOUUVeiohTt8A", "id": 12345}}`))
+	})
+	result, err := client.WaitForTask(context.Background(), "oTUltX4IQMOUUVeiohTt8A:12345", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Completed {
+		t.Errorf("expected a completed task, got %+v", result)
+	}
+}
+
+func TestListAndCancelTasks(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/_tasks":
+			if r.URL.Query().Get("actions") != "*reindex" || r.URL.Query().Get("detailed") != "true" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"nodes": {"n1": {"name": "es-1", "tasks": {
+				"n1:2": {"node": "n1", "id": 2, "action": "indices:data/write/reindex", "start_time_in_millis": 2, "cancellable": true},
+				"n1:1": {"node": "n1", "id": 1, "action": "indices:data/write/reindex", "start_time_in_millis": 1, "cancellable": true}
+			}}}}`))
+		case "/_tasks/n1:1/_cancel":
+			w.Write([]byte(`{"nodes": {}, "task_failures": [{"task_id": 1, "node_id": "n1", "status": "INTERNAL_SERVER_ERROR",
+				"reason": {"type": "illegal_state_exception", "reason": "task with id 1 is already cancelled"}}]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	result, err := client.ListTasks().Actions("*reindex").Detailed(true).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks := result.Tasks()
+	if len(tasks) != 2 || tasks[0].TaskId() != "n1:1" {
+		t.Fatalf("unexpected tasks %+v", tasks)
+	}
+	if err := client.CancelTask(context.Background(), tasks[0].TaskId()); err == nil {
+		t.Error("expected error for a task failure")
+	}
+}
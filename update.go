@@ -0,0 +1,249 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/wh5231/go-elasticsearch/uritemplates"
+	"net/url"
+	"strconv"
+)
+
+// UpdateService updates a document with a partial document or a script.
+//
+//	response, err := client.Update("md_fin_waybill").Id("W123").
+//		Doc(map[string]interface{}{"F_FJScan_Flag": 1}).
+//		RetryOnConflict(3).
+//		Do(ctx)
+type UpdateService struct {
+	client          *Client
+	index           string
+	typ             string
+	id              string
+	doc             interface{}
+	docAsUpsert     bool
+	upsert          interface{}
+	script          interface{}
+	scriptedUpsert  bool
+	detectNoop      *bool
+	fetchSource     bool
+	routing         string
+	refresh         string
+	timeout         string
+	retryOnConflict int
+	ifSeqNo         *int64
+	ifPrimaryTerm   *int64
+}
+
+func NewUpdateService(c *Client) *UpdateService {
+	return &UpdateService{
+		client: c,
+	}
+}
+
+func (this *Client) Update(index string) *UpdateService {
+	return NewUpdateService(this).Index(index)
+}
+
+func (this *UpdateService) Index(index string) *UpdateService {
+	this.index = index
+	return this
+}
+
+// Type sets the type of the document, for Elasticsearch 6.x.
+func (this *UpdateService) Type(typ string) *UpdateService {
+	this.typ = typ
+	return this
+}
+
+func (this *UpdateService) Id(id string) *UpdateService {
+	this.id = id
+	return this
+}
+
+// Doc sets the partial document merged into the document.
+func (this *UpdateService) Doc(doc interface{}) *UpdateService {
+	this.doc = doc
+	return this
+}
+
+// DocAsUpsert indexes the partial document if the document does not exist.
+func (this *UpdateService) DocAsUpsert(docAsUpsert bool) *UpdateService {
+	this.docAsUpsert = docAsUpsert
+	return this
+}
+
+// Upsert sets the document indexed if the document does not exist.
+func (this *UpdateService) Upsert(upsert interface{}) *UpdateService {
+	this.upsert = upsert
+	return this
+}
+
+// Script sets the script updating the document, either a string or a script
+// object like map[string]interface{}{"source": ..., "params": ...}.
+func (this *UpdateService) Script(script interface{}) *UpdateService {
+	this.script = script
+	return this
+}
+
+// ScriptedUpsert runs the script also if the document does not exist.
+func (this *UpdateService) ScriptedUpsert(scriptedUpsert bool) *UpdateService {
+	this.scriptedUpsert = scriptedUpsert
+	return this
+}
+
+// DetectNoop set to false writes the document even if the partial document
+// does not change it.
+func (this *UpdateService) DetectNoop(detectNoop bool) *UpdateService {
+	this.detectNoop = &detectNoop
+	return this
+}
+
+// FetchSource returns the updated document in UpdateResponse.GetResult.
+func (this *UpdateService) FetchSource(fetchSource bool) *UpdateService {
+	this.fetchSource = fetchSource
+	return this
+}
+
+func (this *UpdateService) Routing(routing string) *UpdateService {
+	this.routing = routing
+	return this
+}
+
+// Refresh sets when the change is made visible to search: "true",
+// "wait_for" or "false".
+func (this *UpdateService) Refresh(refresh string) *UpdateService {
+	this.refresh = refresh
+	return this
+}
+
+func (this *UpdateService) Timeout(timeout string) *UpdateService {
+	this.timeout = timeout
+	return this
+}
+
+// RetryOnConflict retries the update up to n times if the document is
+// changed concurrently.
+func (this *UpdateService) RetryOnConflict(n int) *UpdateService {
+	this.retryOnConflict = n
+	return this
+}
+
+// IfSeqNo only updates the document if its sequence number is seqNo, see
+// IndexService.IfSeqNo.
+func (this *UpdateService) IfSeqNo(seqNo int64) *UpdateService {
+	this.ifSeqNo = &seqNo
+	return this
+}
+
+func (this *UpdateService) IfPrimaryTerm(primaryTerm int64) *UpdateService {
+	this.ifPrimaryTerm = &primaryTerm
+	return this
+}
+
+// buildUrl builds "/{index}/_update/{id}", or "/{index}/{type}/{id}/_update"
+// if a type is set for Elasticsearch 6.x.
+func (this *UpdateService) buildUrl() (string, url.Values, error) {
+	var (
+		err  error
+		path string
+	)
+	if this.typ != "" {
+		path, err = uritemplates.Expand("/{index}/{type}/{id}/_update", map[string]string{
+			"index": this.index,
+			"type":  this.typ,
+			"id":    this.id,
+		})
+	} else {
+		path, err = uritemplates.Expand("/{index}/_update/{id}", map[string]string{
+			"index": this.index,
+			"id":    this.id,
+		})
+	}
+	if err != nil {
+		return "", url.Values{}, err
+	}
+	params := url.Values{}
+	if this.routing != "" {
+		params.Set("routing", this.routing)
+	}
+	if this.refresh != "" {
+		params.Set("refresh", this.refresh)
+	}
+	if this.timeout != "" {
+		params.Set("timeout", this.timeout)
+	}
+	if this.retryOnConflict > 0 {
+		params.Set("retry_on_conflict", strconv.Itoa(this.retryOnConflict))
+	}
+	if this.ifSeqNo != nil {
+		params.Set("if_seq_no", strconv.FormatInt(*this.ifSeqNo, 10))
+	}
+	if this.ifPrimaryTerm != nil {
+		params.Set("if_primary_term", strconv.FormatInt(*this.ifPrimaryTerm, 10))
+	}
+	return path, params, nil
+}
+
+// Body returns the body of the update request.
+func (this *UpdateService) Body() (map[string]interface{}, error) {
+	if this.doc == nil && this.script == nil {
+		return nil, fmt.Errorf("update requires a doc or a script")
+	}
+	body := make(map[string]interface{})
+	if this.doc != nil {
+		body["doc"] = this.doc
+	}
+	if this.docAsUpsert {
+		body["doc_as_upsert"] = true
+	}
+	if this.upsert != nil {
+		body["upsert"] = this.upsert
+	}
+	if this.script != nil {
+		body["script"] = this.script
+	}
+	if this.scriptedUpsert {
+		body["scripted_upsert"] = true
+	}
+	if this.detectNoop != nil {
+		body["detect_noop"] = *this.detectNoop
+	}
+	if this.fetchSource {
+		body["_source"] = true
+	}
+	return body, nil
+}
+
+func (this *UpdateService) Do(ctx context.Context) (*UpdateResponse, error) {
+	if this.index == "" {
+		return nil, fmt.Errorf("index is required")
+	}
+	if this.id == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+	path, params, err := this.buildUrl()
+	if err != nil {
+		return nil, err
+	}
+	body, err := this.Body()
+	if err != nil {
+		return nil, err
+	}
+	response, err := this.client.httpRequest(ctx, "POST", path, params, body, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(UpdateResponse)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// UpdateResponse is the response of an update. GetResult holds the updated
+// document if FetchSource was set.
+type UpdateResponse struct {
+	IndexResponse
+	GetResult *GetResult `json:"get,omitempty"`
+}
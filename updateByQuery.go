@@ -0,0 +1,209 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/wh5231/go-elasticsearch/uritemplates"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// UpdateByQueryService updates the documents matching a query with the
+// _update_by_query endpoint, e.g. with a script, or to pick up a mapping
+// change without a script.
+//
+//	response, err := client.UpdateByQuery("md_fin_waybill").
+//		Query(client.Search().AndWhere("<", "F_FJScan_Flag", "1")).
+//		Script(map[string]interface{}{"source": "ctx._source.F_FJScan_Flag = 1"}).
+//		Conflicts("proceed").
+//		Do(ctx)
+type UpdateByQueryService struct {
+	client            *Client
+	index             []string
+	query             *Query
+	script            interface{}
+	conflicts         string
+	maxDocs           int64
+	slices            interface{}
+	scrollSize        int
+	routing           string
+	pipeline          string
+	refresh           bool
+	requestsPerSecond *float64
+	waitForCompletion *bool
+	timeout           string
+}
+
+func NewUpdateByQueryService(c *Client) *UpdateByQueryService {
+	return &UpdateByQueryService{
+		client: c,
+		index:  make([]string, 0),
+	}
+}
+
+func (this *Client) UpdateByQuery(index ...string) *UpdateByQueryService {
+	return NewUpdateByQueryService(this).Index(index...)
+}
+
+func (this *UpdateByQueryService) Index(index ...string) *UpdateByQueryService {
+	this.index = append(this.index, index...)
+	return this
+}
+
+// Query sets the documents to update: those matching the where conditions
+// of query. Without index, the indices of query are used.
+func (this *UpdateByQueryService) Query(query *Query) *UpdateByQueryService {
+	this.query = query
+	return this
+}
+
+// Script sets the script updating the documents, see UpdateService.Script.
+func (this *UpdateByQueryService) Script(script interface{}) *UpdateByQueryService {
+	this.script = script
+	return this
+}
+
+// Conflicts set to "proceed" counts version conflicts instead of aborting.
+func (this *UpdateByQueryService) Conflicts(conflicts string) *UpdateByQueryService {
+	this.conflicts = conflicts
+	return this
+}
+
+func (this *UpdateByQueryService) MaxDocs(maxDocs int64) *UpdateByQueryService {
+	this.maxDocs = maxDocs
+	return this
+}
+
+// Slices splits the update into parallel slices: a number or "auto".
+func (this *UpdateByQueryService) Slices(slices interface{}) *UpdateByQueryService {
+	this.slices = slices
+	return this
+}
+
+// ScrollSize sets the number of documents updated per batch.
+func (this *UpdateByQueryService) ScrollSize(scrollSize int) *UpdateByQueryService {
+	this.scrollSize = scrollSize
+	return this
+}
+
+func (this *UpdateByQueryService) Routing(routing string) *UpdateByQueryService {
+	this.routing = routing
+	return this
+}
+
+func (this *UpdateByQueryService) Pipeline(pipeline string) *UpdateByQueryService {
+	this.pipeline = pipeline
+	return this
+}
+
+func (this *UpdateByQueryService) Refresh(refresh bool) *UpdateByQueryService {
+	this.refresh = refresh
+	return this
+}
+
+// RequestsPerSecond throttles the update; -1 disables throttling.
+func (this *UpdateByQueryService) RequestsPerSecond(requestsPerSecond float64) *UpdateByQueryService {
+	this.requestsPerSecond = &requestsPerSecond
+	return this
+}
+
+// WaitForCompletion set to false runs the update as a task and returns its
+// id in BulkIndexByScrollResponse.Task.
+func (this *UpdateByQueryService) WaitForCompletion(waitForCompletion bool) *UpdateByQueryService {
+	this.waitForCompletion = &waitForCompletion
+	return this
+}
+
+func (this *UpdateByQueryService) Timeout(timeout string) *UpdateByQueryService {
+	this.timeout = timeout
+	return this
+}
+
+// Body returns the body of the request.
+func (this *UpdateByQueryService) Body() (map[string]interface{}, error) {
+	body := make(map[string]interface{})
+	if this.query != nil {
+		builder := QueryBuilder{}
+		query, err := builder.BuildQuery(this.query)
+		if err != nil {
+			return nil, err
+		}
+		if query != nil {
+			body["query"] = query
+		}
+	}
+	if this.script != nil {
+		body["script"] = this.script
+	}
+	if this.maxDocs > 0 {
+		body["max_docs"] = this.maxDocs
+	}
+	return body, nil
+}
+
+func (this *UpdateByQueryService) buildUrl() (string, url.Values, error) {
+	index := this.index
+	if len(index) == 0 && this.query != nil {
+		index = this.query.index
+	}
+	if len(index) == 0 {
+		return "", url.Values{}, fmt.Errorf("update by query requires an index")
+	}
+	path, err := uritemplates.Expand("/{index}/_update_by_query", map[string]string{
+		"index": strings.Join(index, ","),
+	})
+	if err != nil {
+		return "", url.Values{}, err
+	}
+	params := url.Values{}
+	if this.conflicts != "" {
+		params.Set("conflicts", this.conflicts)
+	}
+	if this.slices != nil {
+		params.Set("slices", fmt.Sprint(this.slices))
+	}
+	if this.scrollSize > 0 {
+		params.Set("scroll_size", strconv.Itoa(this.scrollSize))
+	}
+	if this.routing != "" {
+		params.Set("routing", this.routing)
+	}
+	if this.pipeline != "" {
+		params.Set("pipeline", this.pipeline)
+	}
+	if this.refresh {
+		params.Set("refresh", "true")
+	}
+	if this.requestsPerSecond != nil {
+		params.Set("requests_per_second", strconv.FormatFloat(*this.requestsPerSecond, 'f', -1, 64))
+	}
+	if this.waitForCompletion != nil {
+		params.Set("wait_for_completion", strconv.FormatBool(*this.waitForCompletion))
+	}
+	if this.timeout != "" {
+		params.Set("timeout", this.timeout)
+	}
+	return path, params, nil
+}
+
+func (this *UpdateByQueryService) Do(ctx context.Context) (*BulkIndexByScrollResponse, error) {
+	path, params, err := this.buildUrl()
+	if err != nil {
+		return nil, err
+	}
+	body, err := this.Body()
+	if err != nil {
+		return nil, err
+	}
+	response, err := this.client.httpRequest(ctx, "POST", path, params, body, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(BulkIndexByScrollResponse)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
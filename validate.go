@@ -0,0 +1,83 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Validate checks the where conditions of the query against the mappings of
+// its indices with the _validate/query endpoint, without running the search.
+// The explanations hold the rewritten Lucene query per index, or the reason
+// the query is invalid. The query is validated on its indices, also if it has
+// a point in time.
+func (this *Query) Validate(ctx context.Context) (*ValidateResult, error) {
+	builder := QueryBuilder{}
+	path, params, err := this.buildIndexUrl("_validate/query")
+	if err != nil {
+		return nil, err
+	}
+	params.Set("explain", "true")
+	params.Set("rewrite", "true")
+	query, err := builder.BuildQuery(this)
+	if err != nil {
+		return nil, err
+	}
+	var body interface{}
+	if query != nil {
+		body = map[string]interface{}{"query": query}
+	}
+	response, err := this.client.httpRequest(ctx, "POST", path, params, body, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(ValidateResult)
+	if err := json.Unmarshal(response.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ValidateResult is the response of the _validate/query endpoint.
+type ValidateResult struct {
+	Valid        bool                  `json:"valid"`
+	Shards       *ShardsInfo           `json:"_shards,omitempty"`
+	Explanations []ValidateExplanation `json:"explanations,omitempty"`
+}
+
+// ValidateExplanation explains the query on an index. Explanation holds the
+// rewritten Lucene query if the query is valid, Error the reason otherwise.
+type ValidateExplanation struct {
+	Index       string `json:"index"`
+	Shard       *int   `json:"shard,omitempty"`
+	Valid       bool   `json:"valid"`
+	Explanation string `json:"explanation,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// DryRun returns the request Do would send, with the body as JSON, without
+// executing it.
+//
+//	request, err := client.Search("md_fin_waybill").AndWhere("<", "F_FJScan_Flag", "1").DryRun()
+//	fmt.Printf("%s %s?%s\n%s\n", request.Method, request.Path, request.Params.Encode(), request.Body)
+func (this *Query) DryRun() (*PerformRequestOptions, error) {
+	builder := QueryBuilder{}
+	path, params, err := this.BuildUrl()
+	if err != nil {
+		return nil, err
+	}
+	body, err := builder.Build(this)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return &PerformRequestOptions{
+		Method:      "GET",
+		Path:        path,
+		Params:      params,
+		Body:        json.RawMessage(data),
+		ContentType: "application/json",
+	}, nil
+}
@@ -0,0 +1,69 @@
+package go_elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestQueryValidate(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/md_fin_waybill/_validate/query" {
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+		}
+		if r.URL.Query().Get("explain") != "true" || r.URL.Query().Get("rewrite") != "true" {
+			t.Errorf("expected explain and rewrite, got %s", r.URL.RawQuery)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		expected := `{"query":{"bool":{"must":[{"range":{"F_FJScan_Flag":{"lt":"1"}}}]}}}`
+		if string(body) != expected {
+			t.Errorf("expected body %s, got %s", expected, string(body))
+		}
+		w.Write([]byte(`{"valid": false, "explanations": [{"index": "md_fin_waybill", "valid": false,
+			"error": "failed to create query: For input string: \"x\""}]}`))
+	})
+	result, err := client.Search("md_fin_waybill").AndWhere("<", "F_FJScan_Flag", "1").Validate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Valid || len(result.Explanations) != 1 || result.Explanations[0].Error == "" {
+		t.Errorf("expected an invalid query with an error, got %+v", result)
+	}
+}
+
+func TestQueryValidatePointInTime(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/md_fin_waybill/_validate/query" {
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+		}
+		w.Write([]byte(`{"valid": true}`))
+	})
+	result, err := client.Search("md_fin_waybill").PointInTime("pit-1", "1m").Validate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Valid {
+		t.Errorf("expected a valid query, got %+v", result)
+	}
+
+	if _, err := client.Search().PointInTime("pit-1", "1m").Validate(context.Background()); err == nil {
+		t.Error("expected error validating a point in time without indices")
+	}
+}
+
+func TestQueryDryRun(t *testing.T) {
+	client, _ := NewClient()
+	request, err := client.Search("md_fin_waybill").AndWhere("<", "F_FJScan_Flag", "1").Limit(0).DryRun()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.Method != "GET" || request.Path != "/md_fin_waybill/_search" {
+		t.Errorf("unexpected request %s %s", request.Method, request.Path)
+	}
+	expected := `{"aggregations":{},"query":{"bool":{"must":[{"range":{"F_FJScan_Flag":{"lt":"1"}}}]}},"size":0}`
+	if got := string(request.Body.(json.RawMessage)); got != expected {
+		t.Errorf("expected body %s, got %s", expected, got)
+	}
+}